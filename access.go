@@ -1,5 +1,13 @@
 package maputil
 
+import (
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"time"
+)
+
 func CheckEnum(s string, allowed []string) error {
 	for _, v := range allowed {
 		if v == s {
@@ -22,236 +30,210 @@ func GetArray(m map[string]interface{}, key string) ([]interface{}, bool, error)
 	return a, true, err
 }
 
-// GetBoolean fetches a value from the map and converts it to a boolean.
-func GetBoolean(m map[string]interface{}, key string) (bool, bool, error) {
+// GetArrayLength fetches a value from the map, converts it to an array, and
+// ensures its length is within the range.
+func GetArrayLength(m map[string]interface{}, key string, r LengthRange) ([]interface{}, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return false, false, nil
+		return nil, false, nil
 	}
-	b, err := AsBoolean(v)
-	return b, true, err
+	a, err := AsArray(v)
+	if err != nil {
+		return nil, true, err
+	}
+	return a, true, CheckArrayLength(a, r)
 }
 
-// GetInteger fetches a value from the map and converts it to an integer.
-func GetInteger(m map[string]interface{}, key string) (int64, bool, error) {
+// GetBase64Bytes fetches a value from the map and converts it to bytes encoded
+// as a base64 string.
+func GetBase64Bytes(m map[string]interface{}, key string) ([]byte, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return 0, false, nil
+		return nil, false, nil
 	}
-	i, err := AsInteger(v)
-	return i, true, err
+	b, err := AsBase64Bytes(v)
+	return b, true, err
 }
 
-// GetNull fetches a value from the map and ensures it was null.
-func GetNull(m map[string]interface{}, key string) (bool, error) {
+// GetBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number.
+func GetBigFloat(m map[string]interface{}, key string) (*big.Float, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return false, nil
-	}
-	if v != nil {
-		return true, InvalidTypeError{
-			Expected: []string{TypeNull},
-			Actual:   TypeName(v),
-		}
+		return nil, false, nil
 	}
-	return true, nil
+	f, err := AsBigFloat(v)
+	return f, true, err
 }
 
-// GetNumber fetches a value from the map and converts it to a number.
-func GetNumber(m map[string]interface{}, key string) (float64, bool, error) {
+// GetBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer.
+func GetBigInt(m map[string]interface{}, key string) (*big.Int, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return 0, false, nil
+		return nil, false, nil
 	}
-	f, err := AsNumber(v)
-	return f, true, err
+	i, err := AsBigInt(v)
+	return i, true, err
 }
 
-// GetObject fetches a value from the map and converts it to an object.
-func GetObject(m map[string]interface{}, key string) (map[string]interface{}, bool, error) {
+// GetBoolean fetches a value from the map and converts it to a boolean.
+func GetBoolean(m map[string]interface{}, key string) (bool, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return nil, false, nil
+		return false, false, nil
 	}
-	m, err := AsObject(v)
-	return m, true, err
+	b, err := AsBoolean(v)
+	return b, true, err
 }
 
-// GetString fetches a value from the map and converts it to a string.
-func GetString(m map[string]interface{}, key string) (string, bool, error) {
+// GetBooleanMap fetches a value from the map and converts it to an object of
+// booleans.
+func GetBooleanMap(m map[string]interface{}, key string) (map[string]bool, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return "", false, nil
+		return nil, false, nil
 	}
-	s, err := AsString(v)
-	return s, true, err
+	bm, err := AsBooleanMap(v)
+	return bm, true, err
 }
 
-// GetStringEnum fetches a value from the map, converts it to a string, and
-// ensures it is one of the given values.
-func GetStringEnum(m map[string]interface{}, key string, values []string) (string, bool, error) {
+// GetByteSize fetches a value from the map and converts it to a number of
+// bytes.
+func GetByteSize(m map[string]interface{}, key string) (int64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return "", false, nil
-	}
-	s, err := AsString(v)
-	if err != nil {
-		return "", true, err
+		return 0, false, nil
 	}
-
-	return s, true, CheckEnum(s, values)
+	i, err := AsByteSize(v)
+	return i, true, err
 }
 
-// OptionalArray fetches a value from the map and converts it to an array.
-func OptionalArray(m map[string]interface{}, key string, dv []interface{}) ([]interface{}, error) {
+// GetByteSizeRange fetches a value from the map, converts it to a number of
+// bytes, and ensures it is within the range.
+func GetByteSizeRange(m map[string]interface{}, key string, r IntegerRange) (int64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
+		return 0, false, nil
 	}
-	a, err := AsArray(v)
+	i, err := AsByteSize(v)
 	if err != nil {
-		return dv, err
+		return 0, true, err
 	}
-	return a, nil
+	return i, true, r.Check(i)
 }
 
-// OptionalBoolean fetches a value from the map and converts it to a boolean.
-func OptionalBoolean(m map[string]interface{}, key string, dv bool) (bool, error) {
+// GetCIDR fetches a value from the map and converts it to an IP network.
+func GetCIDR(m map[string]interface{}, key string) (*net.IPNet, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	b, err := AsBoolean(v)
-	if err != nil {
-		return dv, err
+		return nil, false, nil
 	}
-	return b, nil
+	n, err := AsCIDR(v)
+	return n, true, err
 }
 
-// OptionalInteger fetches a value from the map and converts it to an integer.
-func OptionalInteger(m map[string]interface{}, key string, dv int64) (int64, error) {
+// GetDuration fetches a value from the map and converts it to a duration.
+func GetDuration(m map[string]interface{}, key string, unit time.Duration) (time.Duration, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	i, err := AsInteger(v)
-	if err != nil {
-		return dv, err
+		return 0, false, nil
 	}
-	return i, nil
+	d, err := AsDuration(v, unit)
+	return d, true, err
 }
 
-// OptionalNull fetches a value from the map and ensures it was null.
-func OptionalNull(m map[string]interface{}, key string) error {
+// GetHostPort fetches a value from the map and converts it to a host and port.
+func GetHostPort(m map[string]interface{}, key string) (HostPort, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return nil
-	}
-	if v != nil {
-		return InvalidTypeError{
-			Expected: []string{TypeNull},
-			Actual:   TypeName(v),
-		}
+		return HostPort{}, false, nil
 	}
-	return nil
+	hp, err := AsHostPort(v)
+	return hp, true, err
 }
 
-// OptionalNumber fetches a value from the map and converts it to a number.
-func OptionalNumber(m map[string]interface{}, key string, dv float64) (float64, error) {
+// GetIP fetches a value from the map and converts it to an IP address.
+func GetIP(m map[string]interface{}, key string) (net.IP, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	n, err := AsNumber(v)
-	if err != nil {
-		return dv, err
+		return nil, false, nil
 	}
-	return n, nil
+	ip, err := AsIP(v)
+	return ip, true, err
 }
 
-// OptionalObject fetches a value from the map and converts it to an object.
-func OptionalObject(m map[string]interface{}, key string, dv map[string]interface{}) (map[string]interface{}, error) {
+// GetInt8 fetches a value from the map and converts it to an 8-bit integer.
+func GetInt8(m map[string]interface{}, key string) (int8, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	o, err := AsObject(v)
-	if err != nil {
-		return dv, err
+		return 0, false, nil
 	}
-	return o, nil
+	i, err := AsInt8(v)
+	return i, true, err
 }
 
-// OptionalString fetches a value from the map and converts it to a string.
-func OptionalString(m map[string]interface{}, key, dv string) (string, error) {
+// GetInt16 fetches a value from the map and converts it to a 16-bit integer.
+func GetInt16(m map[string]interface{}, key string) (int16, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	s, err := AsString(v)
-	if err != nil {
-		return dv, err
+		return 0, false, nil
 	}
-	return s, nil
+	i, err := AsInt16(v)
+	return i, true, err
 }
 
-// GetStringEnum fetches a value from the map, converts it to a string, and
-// ensures it is one of the given values.
-func OptionalStringEnum(m map[string]interface{}, key string, values []string, dv string) (string, error) {
+// GetInt32 fetches a value from the map and converts it to a 32-bit integer.
+func GetInt32(m map[string]interface{}, key string) (int32, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return dv, nil
-	}
-	s, err := AsString(v)
-	if err != nil {
-		return dv, err
-	}
-	if err := CheckEnum(s, values); err != nil {
-		return dv, err
+		return 0, false, nil
 	}
-	return s, nil
+	i, err := AsInt32(v)
+	return i, true, err
 }
 
-// PopArray fetches a value from the map and converts it to an array.
-func PopArray(m map[string]interface{}, key string) ([]interface{}, bool, error) {
+// GetInteger fetches a value from the map and converts it to an integer.
+func GetInteger(m map[string]interface{}, key string) (int64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return nil, false, nil
+		return 0, false, nil
 	}
-	delete(m, key)
-	a, err := AsArray(v)
-	return a, true, err
+	i, err := AsInteger(v)
+	return i, true, err
 }
 
-// PopBoolean fetches a value from the map and converts it to a boolean.
-func PopBoolean(m map[string]interface{}, key string) (bool, bool, error) {
+// GetIntegerMap fetches a value from the map and converts it to an object of
+// integers.
+func GetIntegerMap(m map[string]interface{}, key string) (map[string]int64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return false, false, nil
+		return nil, false, nil
 	}
-	delete(m, key)
-	b, err := AsBoolean(v)
-	return b, true, err
+	im, err := AsIntegerMap(v)
+	return im, true, err
 }
 
-// PopInteger fetches a value from the map and converts it to an integer.
-func PopInteger(m map[string]interface{}, key string) (int64, bool, error) {
+// GetIntegerRange fetches a value from the map, converts it to an integer, and
+// ensures it is within the range.
+func GetIntegerRange(m map[string]interface{}, key string, r IntegerRange) (int64, bool, error) {
 	v, ok := m[key]
 	if !ok {
 		return 0, false, nil
 	}
-	delete(m, key)
 	i, err := AsInteger(v)
-	return i, true, err
+	if err != nil {
+		return 0, true, err
+	}
+	return i, true, r.Check(i)
 }
 
-// PopNull fetches a value from the map and ensures it was null.
-func PopNull(m map[string]interface{}, key string) (bool, error) {
+// GetNull fetches a value from the map and ensures it was null.
+func GetNull(m map[string]interface{}, key string) (bool, error) {
 	v, ok := m[key]
 	if !ok {
 		return false, nil
 	}
-	delete(m, key)
 	if v != nil {
 		return true, InvalidTypeError{
 			Expected: []string{TypeNull},
@@ -261,121 +243,1449 @@ func PopNull(m map[string]interface{}, key string) (bool, error) {
 	return true, nil
 }
 
-// PopNumber fetches a value from the map and converts it to a number.
-func PopNumber(m map[string]interface{}, key string) (float64, bool, error) {
+// GetNumber fetches a value from the map and converts it to a number.
+func GetNumber(m map[string]interface{}, key string) (float64, bool, error) {
 	v, ok := m[key]
 	if !ok {
 		return 0, false, nil
 	}
-	delete(m, key)
-	n, err := AsNumber(v)
-	return n, true, err
+	f, err := AsNumber(v)
+	return f, true, err
 }
 
-// PopObject fetches a value from the map and converts it to an object.
-func PopObject(m map[string]interface{}, key string) (map[string]interface{}, bool, error) {
+// GetNumberMap fetches a value from the map and converts it to an object of
+// numbers.
+func GetNumberMap(m map[string]interface{}, key string) (map[string]float64, bool, error) {
 	v, ok := m[key]
 	if !ok {
 		return nil, false, nil
 	}
-	delete(m, key)
-	o, err := AsObject(v)
-	return o, true, err
-}
-
-// PopString fetches a value from the map and converts it to a string.
-func PopString(m map[string]interface{}, key string) (string, bool, error) {
-	v, ok := m[key]
-	if !ok {
-		return "", false, nil
-	}
-	delete(m, key)
-	s, err := AsString(v)
-	return s, true, err
+	nm, err := AsNumberMap(v)
+	return nm, true, err
 }
 
-// PopStringEnum fetches a value from the map, converts it to a string, and
-// ensures it is one of the given values.
-func PopStringEnum(m map[string]interface{}, key string, values []string) (string, bool, error) {
+// GetNumberRange fetches a value from the map, converts it to a number, and
+// ensures it is within the range.
+func GetNumberRange(m map[string]interface{}, key string, r NumberRange) (float64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return "", false, nil
+		return 0, false, nil
 	}
-	delete(m, key)
-	s, err := AsString(v)
+	n, err := AsNumber(v)
 	if err != nil {
-		return "", true, err
+		return 0, true, err
 	}
-	return s, true, CheckEnum(s, values)
+	return n, true, r.Check(n)
 }
 
-// RequireArray fetches a value from the map and converts it to an array.
-func RequireArray(m map[string]interface{}, key string) ([]interface{}, error) {
+// GetObject fetches a value from the map and converts it to an object.
+func GetObject(m map[string]interface{}, key string) (map[string]interface{}, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return nil, MissingRequiredValueError{Key: key}
+		return nil, false, nil
 	}
-	return AsArray(v)
+	m, err := AsObject(v)
+	return m, true, err
 }
 
-// RequireBoolean fetches a value from the map and converts it to a boolean.
-func RequireBoolean(m map[string]interface{}, key string) (bool, error) {
+// GetObjectMap fetches a value from the map and converts it to an object of
+// objects.
+func GetObjectMap(m map[string]interface{}, key string) (map[string]map[string]interface{}, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return false, MissingRequiredValueError{Key: key}
+		return nil, false, nil
 	}
-	return AsBoolean(v)
+	om, err := AsObjectMap(v)
+	return om, true, err
 }
 
-// RequireInteger fetches a value from the map and converts it to an integer.
-func RequireInteger(m map[string]interface{}, key string) (int64, error) {
+// GetPercent fetches a value from the map and converts it to a fraction.
+func GetPercent(m map[string]interface{}, key string) (float64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return 0, MissingRequiredValueError{Key: key}
+		return 0, false, nil
 	}
-	return AsInteger(v)
+	f, err := AsPercent(v)
+	return f, true, err
 }
 
-// RequireNull fetches a value from the map and ensures it was null.
-func RequireNull(m map[string]interface{}, key string) error {
+// GetPercentRange fetches a value from the map, converts it to a fraction, and
+// ensures it is within the range.
+func GetPercentRange(m map[string]interface{}, key string, r NumberRange) (float64, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return MissingRequiredValueError{Key: key}
+		return 0, false, nil
 	}
-	if v != nil {
-		return InvalidTypeError{
-			Expected: []string{TypeNull},
-			Actual:   TypeName(v),
-		}
+	f, err := AsPercent(v)
+	if err != nil {
+		return 0, true, err
 	}
-	return nil
+	return f, true, r.Check(f)
 }
 
-// RequireNumber fetches a value from the map and converts it to a number.
-func RequireNumber(m map[string]interface{}, key string) (float64, error) {
+// GetRate fetches a value from the map and converts it to a rate.
+func GetRate(m map[string]interface{}, key string) (Rate, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return 0, MissingRequiredValueError{Key: key}
+		return Rate{}, false, nil
 	}
-	return AsNumber(v)
+	r, err := AsRate(v)
+	return r, true, err
 }
 
-// RequireObject fetches a value from the map and converts it to an object.
-func RequireObject(m map[string]interface{}, key string) (map[string]interface{}, error) {
+// GetRegexp fetches a value from the map and converts it to a regular
+// expression.
+func GetRegexp(m map[string]interface{}, key string) (*regexp.Regexp, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return nil, MissingRequiredValueError{Key: key}
+		return nil, false, nil
 	}
-	return AsObject(v)
+	re, err := AsRegexp(v)
+	return re, true, err
 }
 
-// RequireString fetches a value from the map and converts it to a string.
-func RequireString(m map[string]interface{}, key string) (string, error) {
+// GetString fetches a value from the map and converts it to a string.
+func GetString(m map[string]interface{}, key string) (string, bool, error) {
 	v, ok := m[key]
 	if !ok {
-		return "", MissingRequiredValueError{Key: key}
+		return "", false, nil
 	}
-	return AsString(v)
+	s, err := AsString(v)
+	return s, true, err
+}
+
+// GetStringEnum fetches a value from the map, converts it to a string, and
+// ensures it is one of the given values.
+func GetStringEnum(m map[string]interface{}, key string, values []string) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return "", true, err
+	}
+
+	return s, true, CheckEnum(s, values)
+}
+
+// GetStringLength fetches a value from the map, converts it to a string, and
+// ensures its length is within the range.
+func GetStringLength(m map[string]interface{}, key string, r LengthRange) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return "", true, err
+	}
+	return s, true, CheckStringLength(s, r)
+}
+
+// GetStringMap fetches a value from the map and converts it to an object of
+// strings.
+func GetStringMap(m map[string]interface{}, key string) (map[string]string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	sm, err := AsStringMap(v)
+	return sm, true, err
+}
+
+// GetStringPattern fetches a value from the map, converts it to a string, and
+// ensures it matches the regular expression.
+func GetStringPattern(m map[string]interface{}, key string, re *regexp.Regexp) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return "", true, err
+	}
+	return s, true, CheckPattern(s, re)
+}
+
+// GetTime fetches a value from the map and converts it to a time.
+func GetTime(m map[string]interface{}, key string, layouts ...string) (time.Time, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	t, err := AsTime(v, layouts...)
+	return t, true, err
+}
+
+// GetURL fetches a value from the map and converts it to an absolute URL.
+func GetURL(m map[string]interface{}, key string) (*url.URL, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	u, err := AsURL(v)
+	return u, true, err
+}
+
+// GetUint8 fetches a value from the map and converts it to an 8-bit unsigned
+// integer.
+func GetUint8(m map[string]interface{}, key string) (uint8, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	u, err := AsUint8(v)
+	return u, true, err
+}
+
+// GetUint16 fetches a value from the map and converts it to a 16-bit unsigned
+// integer.
+func GetUint16(m map[string]interface{}, key string) (uint16, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	u, err := AsUint16(v)
+	return u, true, err
+}
+
+// GetUint32 fetches a value from the map and converts it to a 32-bit unsigned
+// integer.
+func GetUint32(m map[string]interface{}, key string) (uint32, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	u, err := AsUint32(v)
+	return u, true, err
+}
+
+// GetUint64 fetches a value from the map and converts it to a 64-bit unsigned
+// integer.
+func GetUint64(m map[string]interface{}, key string) (uint64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	u, err := AsUint64(v)
+	return u, true, err
+}
+
+// OptionalArray fetches a value from the map and converts it to an array.
+func OptionalArray(m map[string]interface{}, key string, dv []interface{}) ([]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	a, err := AsArray(v)
+	if err != nil {
+		return dv, err
+	}
+	return a, nil
+}
+
+// OptionalArrayLength fetches a value from the map, converts it to an array,
+// and ensures its length is within the range.
+func OptionalArrayLength(m map[string]interface{}, key string, r LengthRange, dv []interface{}) ([]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	a, err := AsArray(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := CheckArrayLength(a, r); err != nil {
+		return dv, err
+	}
+	return a, nil
+}
+
+// OptionalBase64Bytes fetches a value from the map and converts it to bytes
+// encoded as a base64 string.
+func OptionalBase64Bytes(m map[string]interface{}, key string, dv []byte) ([]byte, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	b, err := AsBase64Bytes(v)
+	if err != nil {
+		return dv, err
+	}
+	return b, nil
+}
+
+// OptionalBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number.
+func OptionalBigFloat(m map[string]interface{}, key string, dv *big.Float) (*big.Float, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	f, err := AsBigFloat(v)
+	if err != nil {
+		return dv, err
+	}
+	return f, nil
+}
+
+// OptionalBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer.
+func OptionalBigInt(m map[string]interface{}, key string, dv *big.Int) (*big.Int, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsBigInt(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalBoolean fetches a value from the map and converts it to a boolean.
+func OptionalBoolean(m map[string]interface{}, key string, dv bool) (bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	b, err := AsBoolean(v)
+	if err != nil {
+		return dv, err
+	}
+	return b, nil
+}
+
+// OptionalBooleanMap fetches a value from the map and converts it to an object
+// of booleans.
+func OptionalBooleanMap(m map[string]interface{}, key string, dv map[string]bool) (map[string]bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	bm, err := AsBooleanMap(v)
+	if err != nil {
+		return dv, err
+	}
+	return bm, nil
+}
+
+// OptionalByteSize fetches a value from the map and converts it to a number of
+// bytes.
+func OptionalByteSize(m map[string]interface{}, key string, dv int64) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsByteSize(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalByteSizeRange fetches a value from the map, converts it to a number
+// of bytes, and ensures it is within the range.
+func OptionalByteSizeRange(m map[string]interface{}, key string, r IntegerRange, dv int64) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsByteSize(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := r.Check(i); err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalCIDR fetches a value from the map and converts it to an IP network.
+func OptionalCIDR(m map[string]interface{}, key string, dv *net.IPNet) (*net.IPNet, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	n, err := AsCIDR(v)
+	if err != nil {
+		return dv, err
+	}
+	return n, nil
+}
+
+// OptionalDuration fetches a value from the map and converts it to a duration.
+func OptionalDuration(m map[string]interface{}, key string, unit, dv time.Duration) (time.Duration, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	d, err := AsDuration(v, unit)
+	if err != nil {
+		return dv, err
+	}
+	return d, nil
+}
+
+// OptionalHostPort fetches a value from the map and converts it to a host and
+// port.
+func OptionalHostPort(m map[string]interface{}, key string, dv HostPort) (HostPort, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	hp, err := AsHostPort(v)
+	if err != nil {
+		return dv, err
+	}
+	return hp, nil
+}
+
+// OptionalIP fetches a value from the map and converts it to an IP address.
+func OptionalIP(m map[string]interface{}, key string, dv net.IP) (net.IP, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	ip, err := AsIP(v)
+	if err != nil {
+		return dv, err
+	}
+	return ip, nil
+}
+
+// OptionalInt8 fetches a value from the map and converts it to an 8-bit
+// integer.
+func OptionalInt8(m map[string]interface{}, key string, dv int8) (int8, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsInt8(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalInt16 fetches a value from the map and converts it to a 16-bit
+// integer.
+func OptionalInt16(m map[string]interface{}, key string, dv int16) (int16, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsInt16(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalInt32 fetches a value from the map and converts it to a 32-bit
+// integer.
+func OptionalInt32(m map[string]interface{}, key string, dv int32) (int32, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsInt32(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalInteger fetches a value from the map and converts it to an integer.
+func OptionalInteger(m map[string]interface{}, key string, dv int64) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsInteger(v)
+	if err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalIntegerMap fetches a value from the map and converts it to an object
+// of integers.
+func OptionalIntegerMap(m map[string]interface{}, key string, dv map[string]int64) (map[string]int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	im, err := AsIntegerMap(v)
+	if err != nil {
+		return dv, err
+	}
+	return im, nil
+}
+
+// OptionalIntegerRange fetches a value from the map, converts it to an
+// integer, and ensures it is within the range.
+func OptionalIntegerRange(m map[string]interface{}, key string, r IntegerRange, dv int64) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	i, err := AsInteger(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := r.Check(i); err != nil {
+		return dv, err
+	}
+	return i, nil
+}
+
+// OptionalNull fetches a value from the map and ensures it was null.
+func OptionalNull(m map[string]interface{}, key string) error {
+	v, ok := m[key]
+	if !ok {
+		return nil
+	}
+	if v != nil {
+		return InvalidTypeError{
+			Expected: []string{TypeNull},
+			Actual:   TypeName(v),
+		}
+	}
+	return nil
+}
+
+// OptionalNumber fetches a value from the map and converts it to a number.
+func OptionalNumber(m map[string]interface{}, key string, dv float64) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	n, err := AsNumber(v)
+	if err != nil {
+		return dv, err
+	}
+	return n, nil
+}
+
+// OptionalNumberMap fetches a value from the map and converts it to an object
+// of numbers.
+func OptionalNumberMap(m map[string]interface{}, key string, dv map[string]float64) (map[string]float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	nm, err := AsNumberMap(v)
+	if err != nil {
+		return dv, err
+	}
+	return nm, nil
+}
+
+// OptionalNumberRange fetches a value from the map, converts it to a number,
+// and ensures it is within the range.
+func OptionalNumberRange(m map[string]interface{}, key string, r NumberRange, dv float64) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	n, err := AsNumber(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := r.Check(n); err != nil {
+		return dv, err
+	}
+	return n, nil
+}
+
+// OptionalObject fetches a value from the map and converts it to an object.
+func OptionalObject(m map[string]interface{}, key string, dv map[string]interface{}) (map[string]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	o, err := AsObject(v)
+	if err != nil {
+		return dv, err
+	}
+	return o, nil
+}
+
+// OptionalObjectMap fetches a value from the map and converts it to an object
+// of objects.
+func OptionalObjectMap(
+	m map[string]interface{},
+	key string,
+	dv map[string]map[string]interface{},
+) (map[string]map[string]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	om, err := AsObjectMap(v)
+	if err != nil {
+		return dv, err
+	}
+	return om, nil
+}
+
+// OptionalPercent fetches a value from the map and converts it to a fraction.
+func OptionalPercent(m map[string]interface{}, key string, dv float64) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	f, err := AsPercent(v)
+	if err != nil {
+		return dv, err
+	}
+	return f, nil
+}
+
+// OptionalPercentRange fetches a value from the map, converts it to a
+// fraction, and ensures it is within the range.
+func OptionalPercentRange(m map[string]interface{}, key string, r NumberRange, dv float64) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	f, err := AsPercent(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := r.Check(f); err != nil {
+		return dv, err
+	}
+	return f, nil
+}
+
+// OptionalRate fetches a value from the map and converts it to a rate.
+func OptionalRate(m map[string]interface{}, key string, dv Rate) (Rate, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	r, err := AsRate(v)
+	if err != nil {
+		return dv, err
+	}
+	return r, nil
+}
+
+// OptionalRegexp fetches a value from the map and converts it to a regular
+// expression.
+func OptionalRegexp(m map[string]interface{}, key string, dv *regexp.Regexp) (*regexp.Regexp, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	re, err := AsRegexp(v)
+	if err != nil {
+		return dv, err
+	}
+	return re, nil
+}
+
+// OptionalString fetches a value from the map and converts it to a string.
+func OptionalString(m map[string]interface{}, key, dv string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return dv, err
+	}
+	return s, nil
+}
+
+// GetStringEnum fetches a value from the map, converts it to a string, and
+// ensures it is one of the given values.
+func OptionalStringEnum(m map[string]interface{}, key string, values []string, dv string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := CheckEnum(s, values); err != nil {
+		return dv, err
+	}
+	return s, nil
+}
+
+// OptionalStringLength fetches a value from the map, converts it to a string,
+// and ensures its length is within the range.
+func OptionalStringLength(m map[string]interface{}, key string, r LengthRange, dv string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := CheckStringLength(s, r); err != nil {
+		return dv, err
+	}
+	return s, nil
+}
+
+// OptionalStringMap fetches a value from the map and converts it to an object
+// of strings.
+func OptionalStringMap(m map[string]interface{}, key string, dv map[string]string) (map[string]string, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	sm, err := AsStringMap(v)
+	if err != nil {
+		return dv, err
+	}
+	return sm, nil
+}
+
+// OptionalStringPattern fetches a value from the map, converts it to a string,
+// and ensures it matches the regular expression.
+func OptionalStringPattern(m map[string]interface{}, key string, re *regexp.Regexp, dv string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return dv, err
+	}
+	if err := CheckPattern(s, re); err != nil {
+		return dv, err
+	}
+	return s, nil
+}
+
+// OptionalTime fetches a value from the map and converts it to a time.
+func OptionalTime(m map[string]interface{}, key string, dv time.Time, layouts ...string) (time.Time, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	t, err := AsTime(v, layouts...)
+	if err != nil {
+		return dv, err
+	}
+	return t, nil
+}
+
+// OptionalURL fetches a value from the map and converts it to an absolute URL.
+func OptionalURL(m map[string]interface{}, key string, dv *url.URL) (*url.URL, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	u, err := AsURL(v)
+	if err != nil {
+		return dv, err
+	}
+	return u, nil
+}
+
+// OptionalUint8 fetches a value from the map and converts it to an 8-bit
+// unsigned integer.
+func OptionalUint8(m map[string]interface{}, key string, dv uint8) (uint8, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	u, err := AsUint8(v)
+	if err != nil {
+		return dv, err
+	}
+	return u, nil
+}
+
+// OptionalUint16 fetches a value from the map and converts it to a 16-bit
+// unsigned integer.
+func OptionalUint16(m map[string]interface{}, key string, dv uint16) (uint16, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	u, err := AsUint16(v)
+	if err != nil {
+		return dv, err
+	}
+	return u, nil
+}
+
+// OptionalUint32 fetches a value from the map and converts it to a 32-bit
+// unsigned integer.
+func OptionalUint32(m map[string]interface{}, key string, dv uint32) (uint32, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	u, err := AsUint32(v)
+	if err != nil {
+		return dv, err
+	}
+	return u, nil
+}
+
+// OptionalUint64 fetches a value from the map and converts it to a 64-bit
+// unsigned integer.
+func OptionalUint64(m map[string]interface{}, key string, dv uint64) (uint64, error) {
+	v, ok := m[key]
+	if !ok {
+		return dv, nil
+	}
+	u, err := AsUint64(v)
+	if err != nil {
+		return dv, err
+	}
+	return u, nil
+}
+
+// PopArray fetches a value from the map and converts it to an array.
+func PopArray(m map[string]interface{}, key string) ([]interface{}, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	a, err := AsArray(v)
+	return a, true, err
+}
+
+// PopBase64Bytes fetches a value from the map and converts it to bytes encoded
+// as a base64 string.
+func PopBase64Bytes(m map[string]interface{}, key string) ([]byte, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	b, err := AsBase64Bytes(v)
+	return b, true, err
+}
+
+// PopBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number.
+func PopBigFloat(m map[string]interface{}, key string) (*big.Float, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	f, err := AsBigFloat(v)
+	return f, true, err
+}
+
+// PopBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer.
+func PopBigInt(m map[string]interface{}, key string) (*big.Int, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	i, err := AsBigInt(v)
+	return i, true, err
+}
+
+// PopBoolean fetches a value from the map and converts it to a boolean.
+func PopBoolean(m map[string]interface{}, key string) (bool, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return false, false, nil
+	}
+	delete(m, key)
+	b, err := AsBoolean(v)
+	return b, true, err
+}
+
+// PopByteSize fetches a value from the map and converts it to a number of
+// bytes.
+func PopByteSize(m map[string]interface{}, key string) (int64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	i, err := AsByteSize(v)
+	return i, true, err
+}
+
+// PopCIDR fetches a value from the map and converts it to an IP network.
+func PopCIDR(m map[string]interface{}, key string) (*net.IPNet, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	n, err := AsCIDR(v)
+	return n, true, err
+}
+
+// PopDuration fetches a value from the map and converts it to a duration.
+func PopDuration(m map[string]interface{}, key string, unit time.Duration) (time.Duration, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	d, err := AsDuration(v, unit)
+	return d, true, err
+}
+
+// PopHostPort fetches a value from the map and converts it to a host and port.
+func PopHostPort(m map[string]interface{}, key string) (HostPort, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return HostPort{}, false, nil
+	}
+	delete(m, key)
+	hp, err := AsHostPort(v)
+	return hp, true, err
+}
+
+// PopIP fetches a value from the map and converts it to an IP address.
+func PopIP(m map[string]interface{}, key string) (net.IP, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	ip, err := AsIP(v)
+	return ip, true, err
+}
+
+// PopInt8 fetches a value from the map and converts it to an 8-bit integer.
+func PopInt8(m map[string]interface{}, key string) (int8, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	i, err := AsInt8(v)
+	return i, true, err
+}
+
+// PopInt16 fetches a value from the map and converts it to a 16-bit integer.
+func PopInt16(m map[string]interface{}, key string) (int16, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	i, err := AsInt16(v)
+	return i, true, err
+}
+
+// PopInt32 fetches a value from the map and converts it to a 32-bit integer.
+func PopInt32(m map[string]interface{}, key string) (int32, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	i, err := AsInt32(v)
+	return i, true, err
+}
+
+// PopInteger fetches a value from the map and converts it to an integer.
+func PopInteger(m map[string]interface{}, key string) (int64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	i, err := AsInteger(v)
+	return i, true, err
+}
+
+// PopNull fetches a value from the map and ensures it was null.
+func PopNull(m map[string]interface{}, key string) (bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return false, nil
+	}
+	delete(m, key)
+	if v != nil {
+		return true, InvalidTypeError{
+			Expected: []string{TypeNull},
+			Actual:   TypeName(v),
+		}
+	}
+	return true, nil
+}
+
+// PopNumber fetches a value from the map and converts it to a number.
+func PopNumber(m map[string]interface{}, key string) (float64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	n, err := AsNumber(v)
+	return n, true, err
+}
+
+// PopObject fetches a value from the map and converts it to an object.
+func PopObject(m map[string]interface{}, key string) (map[string]interface{}, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	o, err := AsObject(v)
+	return o, true, err
+}
+
+// PopPercent fetches a value from the map and converts it to a fraction.
+func PopPercent(m map[string]interface{}, key string) (float64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	f, err := AsPercent(v)
+	return f, true, err
+}
+
+// PopRate fetches a value from the map and converts it to a rate.
+func PopRate(m map[string]interface{}, key string) (Rate, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return Rate{}, false, nil
+	}
+	delete(m, key)
+	r, err := AsRate(v)
+	return r, true, err
+}
+
+// PopRegexp fetches a value from the map and converts it to a regular
+// expression.
+func PopRegexp(m map[string]interface{}, key string) (*regexp.Regexp, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	re, err := AsRegexp(v)
+	return re, true, err
+}
+
+// PopString fetches a value from the map and converts it to a string.
+func PopString(m map[string]interface{}, key string) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	delete(m, key)
+	s, err := AsString(v)
+	return s, true, err
+}
+
+// PopStringEnum fetches a value from the map, converts it to a string, and
+// ensures it is one of the given values.
+func PopStringEnum(m map[string]interface{}, key string, values []string) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	delete(m, key)
+	s, err := AsString(v)
+	if err != nil {
+		return "", true, err
+	}
+	return s, true, CheckEnum(s, values)
+}
+
+// PopTime fetches a value from the map and converts it to a time.
+func PopTime(m map[string]interface{}, key string, layouts ...string) (time.Time, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	delete(m, key)
+	t, err := AsTime(v, layouts...)
+	return t, true, err
+}
+
+// PopURL fetches a value from the map and converts it to an absolute URL.
+func PopURL(m map[string]interface{}, key string) (*url.URL, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m, key)
+	u, err := AsURL(v)
+	return u, true, err
+}
+
+// PopUint8 fetches a value from the map and converts it to an 8-bit unsigned
+// integer.
+func PopUint8(m map[string]interface{}, key string) (uint8, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	u, err := AsUint8(v)
+	return u, true, err
+}
+
+// PopUint16 fetches a value from the map and converts it to a 16-bit unsigned
+// integer.
+func PopUint16(m map[string]interface{}, key string) (uint16, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	u, err := AsUint16(v)
+	return u, true, err
+}
+
+// PopUint32 fetches a value from the map and converts it to a 32-bit unsigned
+// integer.
+func PopUint32(m map[string]interface{}, key string) (uint32, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	u, err := AsUint32(v)
+	return u, true, err
+}
+
+// PopUint64 fetches a value from the map and converts it to a 64-bit unsigned
+// integer.
+func PopUint64(m map[string]interface{}, key string) (uint64, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(m, key)
+	u, err := AsUint64(v)
+	return u, true, err
+}
+
+// RequireArray fetches a value from the map and converts it to an array.
+func RequireArray(m map[string]interface{}, key string) ([]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsArray(v)
+}
+
+// RequireArrayLength fetches a value from the map, converts it to an array,
+// and ensures its length is within the range.
+func RequireArrayLength(m map[string]interface{}, key string, r LengthRange) ([]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	a, err := AsArray(v)
+	if err != nil {
+		return nil, err
+	}
+	return a, CheckArrayLength(a, r)
+}
+
+// RequireBase64Bytes fetches a value from the map and converts it to bytes
+// encoded as a base64 string.
+func RequireBase64Bytes(m map[string]interface{}, key string) ([]byte, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsBase64Bytes(v)
+}
+
+// RequireBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number.
+func RequireBigFloat(m map[string]interface{}, key string) (*big.Float, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsBigFloat(v)
+}
+
+// RequireBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer.
+func RequireBigInt(m map[string]interface{}, key string) (*big.Int, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsBigInt(v)
+}
+
+// RequireBoolean fetches a value from the map and converts it to a boolean.
+func RequireBoolean(m map[string]interface{}, key string) (bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return false, MissingRequiredValueError{Key: key}
+	}
+	return AsBoolean(v)
+}
+
+// RequireBooleanMap fetches a value from the map and converts it to an object
+// of booleans.
+func RequireBooleanMap(m map[string]interface{}, key string) (map[string]bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsBooleanMap(v)
+}
+
+// RequireByteSize fetches a value from the map and converts it to a number of
+// bytes.
+func RequireByteSize(m map[string]interface{}, key string) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsByteSize(v)
+}
+
+// RequireByteSizeRange fetches a value from the map, converts it to a number
+// of bytes, and ensures it is within the range.
+func RequireByteSizeRange(m map[string]interface{}, key string, r IntegerRange) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	i, err := AsByteSize(v)
+	if err != nil {
+		return 0, err
+	}
+	return i, r.Check(i)
+}
+
+// RequireCIDR fetches a value from the map and converts it to an IP network.
+func RequireCIDR(m map[string]interface{}, key string) (*net.IPNet, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsCIDR(v)
+}
+
+// RequireDuration fetches a value from the map and converts it to a duration.
+func RequireDuration(m map[string]interface{}, key string, unit time.Duration) (time.Duration, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsDuration(v, unit)
+}
+
+// RequireHostPort fetches a value from the map and converts it to a host and
+// port.
+func RequireHostPort(m map[string]interface{}, key string) (HostPort, error) {
+	v, ok := m[key]
+	if !ok {
+		return HostPort{}, MissingRequiredValueError{Key: key}
+	}
+	return AsHostPort(v)
+}
+
+// RequireIP fetches a value from the map and converts it to an IP address.
+func RequireIP(m map[string]interface{}, key string) (net.IP, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsIP(v)
+}
+
+// RequireInt8 fetches a value from the map and converts it to an 8-bit
+// integer.
+func RequireInt8(m map[string]interface{}, key string) (int8, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsInt8(v)
+}
+
+// RequireInt16 fetches a value from the map and converts it to a 16-bit
+// integer.
+func RequireInt16(m map[string]interface{}, key string) (int16, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsInt16(v)
+}
+
+// RequireInt32 fetches a value from the map and converts it to a 32-bit
+// integer.
+func RequireInt32(m map[string]interface{}, key string) (int32, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsInt32(v)
+}
+
+// RequireInteger fetches a value from the map and converts it to an integer.
+func RequireInteger(m map[string]interface{}, key string) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsInteger(v)
+}
+
+// RequireIntegerMap fetches a value from the map and converts it to an object
+// of integers.
+func RequireIntegerMap(m map[string]interface{}, key string) (map[string]int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsIntegerMap(v)
+}
+
+// RequireIntegerRange fetches a value from the map, converts it to an integer,
+// and ensures it is within the range.
+func RequireIntegerRange(m map[string]interface{}, key string, r IntegerRange) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	i, err := AsInteger(v)
+	if err != nil {
+		return 0, err
+	}
+	return i, r.Check(i)
+}
+
+// RequireNull fetches a value from the map and ensures it was null.
+func RequireNull(m map[string]interface{}, key string) error {
+	v, ok := m[key]
+	if !ok {
+		return MissingRequiredValueError{Key: key}
+	}
+	if v != nil {
+		return InvalidTypeError{
+			Expected: []string{TypeNull},
+			Actual:   TypeName(v),
+		}
+	}
+	return nil
+}
+
+// RequireNumber fetches a value from the map and converts it to a number.
+func RequireNumber(m map[string]interface{}, key string) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsNumber(v)
+}
+
+// RequireNumberMap fetches a value from the map and converts it to an object
+// of numbers.
+func RequireNumberMap(m map[string]interface{}, key string) (map[string]float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsNumberMap(v)
+}
+
+// RequireNumberRange fetches a value from the map, converts it to a number,
+// and ensures it is within the range.
+func RequireNumberRange(m map[string]interface{}, key string, r NumberRange) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	n, err := AsNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return n, r.Check(n)
+}
+
+// RequireObject fetches a value from the map and converts it to an object.
+func RequireObject(m map[string]interface{}, key string) (map[string]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsObject(v)
+}
+
+// RequireObjectMap fetches a value from the map and converts it to an object
+// of objects.
+func RequireObjectMap(m map[string]interface{}, key string) (map[string]map[string]interface{}, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsObjectMap(v)
+}
+
+// RequirePercent fetches a value from the map and converts it to a fraction.
+func RequirePercent(m map[string]interface{}, key string) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsPercent(v)
+}
+
+// RequirePercentRange fetches a value from the map, converts it to a fraction,
+// and ensures it is within the range.
+func RequirePercentRange(m map[string]interface{}, key string, r NumberRange) (float64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	f, err := AsPercent(v)
+	if err != nil {
+		return 0, err
+	}
+	return f, r.Check(f)
+}
+
+// RequireRate fetches a value from the map and converts it to a rate.
+func RequireRate(m map[string]interface{}, key string) (Rate, error) {
+	v, ok := m[key]
+	if !ok {
+		return Rate{}, MissingRequiredValueError{Key: key}
+	}
+	return AsRate(v)
+}
+
+// RequireRegexp fetches a value from the map and converts it to a regular
+// expression.
+func RequireRegexp(m map[string]interface{}, key string) (*regexp.Regexp, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsRegexp(v)
+}
+
+// RequireString fetches a value from the map and converts it to a string.
+func RequireString(m map[string]interface{}, key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", MissingRequiredValueError{Key: key}
+	}
+	return AsString(v)
 }
 
 // RequireStringEnum fetches a value from the map, converts it to a string, and
@@ -392,3 +1702,99 @@ func RequireStringEnum(m map[string]interface{}, key string, values []string) (s
 
 	return s, CheckEnum(s, values)
 }
+
+// RequireStringLength fetches a value from the map, converts it to a string,
+// and ensures its length is within the range.
+func RequireStringLength(m map[string]interface{}, key string, r LengthRange) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", MissingRequiredValueError{Key: key}
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return "", err
+	}
+	return s, CheckStringLength(s, r)
+}
+
+// RequireStringMap fetches a value from the map and converts it to an object
+// of strings.
+func RequireStringMap(m map[string]interface{}, key string) (map[string]string, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsStringMap(v)
+}
+
+// RequireStringPattern fetches a value from the map, converts it to a string,
+// and ensures it matches the regular expression.
+func RequireStringPattern(m map[string]interface{}, key string, re *regexp.Regexp) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", MissingRequiredValueError{Key: key}
+	}
+	s, err := AsString(v)
+	if err != nil {
+		return "", err
+	}
+	return s, CheckPattern(s, re)
+}
+
+// RequireTime fetches a value from the map and converts it to a time.
+func RequireTime(m map[string]interface{}, key string, layouts ...string) (time.Time, error) {
+	v, ok := m[key]
+	if !ok {
+		return time.Time{}, MissingRequiredValueError{Key: key}
+	}
+	return AsTime(v, layouts...)
+}
+
+// RequireURL fetches a value from the map and converts it to an absolute URL.
+func RequireURL(m map[string]interface{}, key string) (*url.URL, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, MissingRequiredValueError{Key: key}
+	}
+	return AsURL(v)
+}
+
+// RequireUint8 fetches a value from the map and converts it to an 8-bit
+// unsigned integer.
+func RequireUint8(m map[string]interface{}, key string) (uint8, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsUint8(v)
+}
+
+// RequireUint16 fetches a value from the map and converts it to a 16-bit
+// unsigned integer.
+func RequireUint16(m map[string]interface{}, key string) (uint16, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsUint16(v)
+}
+
+// RequireUint32 fetches a value from the map and converts it to a 32-bit
+// unsigned integer.
+func RequireUint32(m map[string]interface{}, key string) (uint32, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsUint32(v)
+}
+
+// RequireUint64 fetches a value from the map and converts it to a 64-bit
+// unsigned integer.
+func RequireUint64(m map[string]interface{}, key string) (uint64, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, MissingRequiredValueError{Key: key}
+	}
+	return AsUint64(v)
+}
@@ -0,0 +1,132 @@
+package maputil
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// BigFloatPrecision is the precision, in bits, of the big.Float values
+// returned by AsBigFloat for values given as decimal strings.
+//
+// 256 bits hold around 77 significant decimal digits.
+const BigFloatPrecision = 256
+
+// AsBigInt attempts to coerce the value into an arbitrary-precision integer.
+//
+// Unlike AsInteger, AsBigInt accepts integers of any size when given a
+// GenericNumber such as json.Number, converting its decimal representation
+// exactly. Values such as 1e3 or 2.0 are accepted as they have no fractional
+// part.
+//
+// Strings are accepted if they hold a decimal integer as written in JSON, such
+// as "-12"; an InvalidValueError is returned for any other string.
+func AsBigInt(v interface{}) (*big.Int, error) {
+	switch d := v.(type) {
+	case string:
+		if integerGrammar.MatchString(d) {
+			i, _ := new(big.Int).SetString(d, 10)
+			return i, nil
+		}
+		return nil, InvalidValueError{Value: strconv.Quote(d), Expected: "decimal integer"}
+	case int, int8, int16, int32, int64:
+		i, err := AsInteger(v)
+		return big.NewInt(i), err
+	case uint, uint8, uint16, uint32, uint64:
+		u, err := AsUint64(v)
+		return new(big.Int).SetUint64(u), err
+	case float32:
+		return bigIntFromFloat(float64(d))
+	case float64:
+		return bigIntFromFloat(d)
+	case GenericNumber:
+		if i, ok := new(big.Int).SetString(d.String(), 10); ok {
+			return i, nil
+		}
+		if r, ok := new(big.Rat).SetString(d.String()); ok {
+			if !r.IsInt() {
+				return nil, InvalidTypeError{
+					Expected: []string{TypeInteger},
+					Actual:   TypeNumber,
+				}
+			}
+			return new(big.Int).Set(r.Num()), nil
+		}
+		f, err := d.Float64()
+		if err != nil {
+			break
+		}
+		return bigIntFromFloat(f)
+	}
+	return nil, InvalidTypeError{
+		Expected: []string{TypeInteger},
+		Actual:   TypeName(v),
+	}
+}
+
+func bigIntFromFloat(f float64) (*big.Int, error) {
+	if math.IsInf(f, 0) || math.Trunc(f) != f {
+		return nil, InvalidTypeError{
+			Expected: []string{TypeInteger},
+			Actual:   TypeNumber,
+		}
+	}
+	i, _ := big.NewFloat(f).Int(nil)
+	return i, nil
+}
+
+// AsBigFloat attempts to coerce the value into an arbitrary-precision
+// floating point number.
+//
+// Integers are converted exactly. A GenericNumber such as json.Number is
+// parsed from its decimal representation with BigFloatPrecision bits of
+// precision, avoiding the rounding of AsNumber.
+//
+// Strings are accepted if they hold a decimal number as written in JSON, such
+// as "0.25" or "1e-3", and are parsed in the same way; an InvalidValueError is
+// returned for any other string.
+func AsBigFloat(v interface{}) (*big.Float, error) {
+	switch d := v.(type) {
+	case string:
+		if numberGrammar.MatchString(d) {
+			f, _, err := big.ParseFloat(d, 10, BigFloatPrecision, big.ToNearestEven)
+			if err == nil {
+				return f, nil
+			}
+		}
+		return nil, InvalidValueError{Value: strconv.Quote(d), Expected: "decimal number"}
+	case int, int8, int16, int32, int64:
+		i, err := AsInteger(v)
+		return new(big.Float).SetInt64(i), err
+	case uint, uint8, uint16, uint32, uint64:
+		u, err := AsUint64(v)
+		return new(big.Float).SetUint64(u), err
+	case float32:
+		return bigFloatFromFloat(float64(d))
+	case float64:
+		return bigFloatFromFloat(d)
+	case GenericNumber:
+		if f, ok := new(big.Float).SetPrec(BigFloatPrecision).SetString(d.String()); ok {
+			return f, nil
+		}
+		f, err := d.Float64()
+		if err != nil {
+			break
+		}
+		return bigFloatFromFloat(f)
+	}
+	return nil, InvalidTypeError{
+		Expected: []string{TypeNumber},
+		Actual:   TypeName(v),
+	}
+}
+
+func bigFloatFromFloat(f float64) (*big.Float, error) {
+	if math.IsNaN(f) {
+		return nil, InvalidTypeError{
+			Expected: []string{TypeNumber},
+			Actual:   "NaN",
+		}
+	}
+	return big.NewFloat(f), nil
+}
@@ -0,0 +1,166 @@
+package maputil_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsBigInt(t *testing.T) {
+	t.Parallel()
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected *big.Int
+	}{
+		{"Int", 10, big.NewInt(10)},
+		{"Int8", int8(-8), big.NewInt(-8)},
+		{"Uint64", uint64(math.MaxUint64), new(big.Int).SetUint64(math.MaxUint64)},
+		{"Float", 1e3, big.NewInt(1000)},
+		{"Float32", float32(-2), big.NewInt(-2)},
+		{"Number", json.Number("42"), big.NewInt(42)},
+		{"BigNumber", json.Number("123456789012345678901234567890"), huge},
+		{"Exponent", json.Number("1.5e3"), big.NewInt(1500)},
+		{"String", "123456789012345678901234567890", huge},
+		{"NegativeString", "-42", big.NewInt(-42)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			i, err := maputil.AsBigInt(tc.data)
+			require.NoError(t, err)
+			require.Equal(t, 0, tc.expected.Cmp(i), "expected %s, got %s", tc.expected, i)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, v := range []interface{}{1.5, math.Inf(1), json.Number("0.5")} {
+			_, err := maputil.AsBigInt(v)
+			require.EqualError(t, err, maputil.InvalidTypeError{
+				Expected: []string{maputil.TypeInteger},
+				Actual:   maputil.TypeNumber,
+			}.Error())
+		}
+		_, err := maputil.AsBigInt(true)
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeInteger},
+			Actual:   maputil.TypeBoolean,
+		}.Error())
+	})
+	t.Run("MalformedString", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{testString, "", "1.5", "1e3", "+1", "0x10", "1_000", " 1", "007"} {
+			_, err := maputil.AsBigInt(s)
+			require.Equal(t, maputil.InvalidValueError{
+				Value:    strconv.Quote(s),
+				Expected: "decimal integer",
+			}, err, "%q", s)
+		}
+	})
+}
+
+func TestAsBigFloat(t *testing.T) {
+	t.Parallel()
+	t.Run("Values", func(t *testing.T) {
+		t.Parallel()
+		for _, v := range []interface{}{2, uint8(2), 2.0, float32(2), json.Number("2")} {
+			f, err := maputil.AsBigFloat(v)
+			require.NoError(t, err)
+			require.Equal(t, 0, f.Cmp(big.NewFloat(2)), "%T", v)
+		}
+	})
+	t.Run("Precision", func(t *testing.T) {
+		t.Parallel()
+		const digits = "0.1000000000000000000000000001"
+		f, err := maputil.AsBigFloat(json.Number(digits))
+		require.NoError(t, err)
+		require.Equal(t, uint(maputil.BigFloatPrecision), f.Prec())
+		require.Equal(t, digits, f.Text('f', 28))
+
+		// The same value converted through float64 loses the trailing digit.
+		n, err := maputil.AsNumber(json.Number(digits))
+		require.NoError(t, err)
+		require.Equal(t, "0.1000000000000000055511151231", big.NewFloat(n).Text('f', 28))
+	})
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+		const digits = "0.1000000000000000000000000001"
+		f, err := maputil.AsBigFloat(digits)
+		require.NoError(t, err)
+		require.Equal(t, uint(maputil.BigFloatPrecision), f.Prec())
+		require.Equal(t, digits, f.Text('f', 28))
+
+		f, err = maputil.AsBigFloat("-1.5e3")
+		require.NoError(t, err)
+		require.Equal(t, 0, f.Cmp(big.NewFloat(-1500)))
+	})
+	t.Run("MalformedString", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{testString, "", "Inf", "NaN", "0x1p3", "1_000", ".5", "1e99999999999"} {
+			_, err := maputil.AsBigFloat(s)
+			require.Equal(t, maputil.InvalidValueError{
+				Value:    strconv.Quote(s),
+				Expected: "decimal number",
+			}, err, "%q", s)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := maputil.AsBigFloat(math.NaN())
+		require.Error(t, err)
+		_, err = maputil.AsBigFloat(true)
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeNumber},
+			Actual:   maputil.TypeBoolean,
+		}.Error())
+	})
+}
+
+func TestBigAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		keyGood:   json.Number("123456789012345678901234567890"),
+		keyBad:    testString,
+		keyBadVal: 1.5,
+	}
+
+	i, err := maputil.RequireBigInt(m, keyGood)
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890", i.String())
+	_, err = maputil.RequireBigInt(m, keyMissing)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+	_, err = maputil.RequireBigInt(m, keyBadVal)
+	require.Error(t, err)
+
+	dv := big.NewInt(7)
+	i, err = maputil.OptionalBigInt(m, keyBad, dv)
+	require.Error(t, err)
+	require.Same(t, dv, i)
+	i, err = maputil.OptionalBigInt(m, keyMissing, dv)
+	require.NoError(t, err)
+	require.Same(t, dv, i)
+
+	f, ok, err := maputil.GetBigFloat(m, keyBadVal)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "1.5", f.String())
+	_, ok, err = maputil.GetBigFloat(m, keyMissing)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	d := maputil.Copy(m)
+	f, ok, err = maputil.PopBigFloat(d, keyGood)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "123456789012345678901234567890", f.Text('f', 0))
+	require.NotContains(t, d, keyGood)
+}
@@ -0,0 +1,682 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tvarney/maputil/internal/tags"
+)
+
+// directive marks a type declaration for generation when no -type flag is
+// given.
+const directive = "//maputil:unpack"
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindBoolean
+	kindInteger
+	kindNumber
+	kindObject
+	kindArray
+	kindStruct
+	kindPointer
+	kindSlice
+)
+
+// typeRef is a resolved field type.
+type typeRef struct {
+	kind    kind
+	name    string
+	builtin string
+	elem    *typeRef
+}
+
+// scalar holds the unpack function suffix and Go type for the scalar kinds.
+var scalars = map[kind]struct {
+	fn   string
+	base string
+	zero string
+}{
+	kindString:  {fn: "String", base: "string", zero: `""`},
+	kindBoolean: {fn: "Boolean", base: "bool", zero: "false"},
+	kindInteger: {fn: "Integer", base: "int64", zero: "0"},
+	kindNumber:  {fn: "Number", base: "float64", zero: "0"},
+}
+
+// sized holds the unpack function suffix and Go type for the integer types
+// which have an accessor of their own width.
+var sized = map[string]struct {
+	fn   string
+	base string
+}{
+	"int8":   {fn: "Int8", base: "int8"},
+	"int16":  {fn: "Int16", base: "int16"},
+	"int32":  {fn: "Int32", base: "int32"},
+	"rune":   {fn: "Int32", base: "rune"},
+	"uint8":  {fn: "Uint8", base: "uint8"},
+	"byte":   {fn: "Uint8", base: "byte"},
+	"uint16": {fn: "Uint16", base: "uint16"},
+	"uint32": {fn: "Uint32", base: "uint32"},
+	"uint64": {fn: "Uint64", base: "uint64"},
+}
+
+// converters holds the unpack function which converts a single value to each
+// numeric type other than int64 and float64. They are used for the elements of
+// slices, and for the types without an accessor of their own, so that values
+// which do not fit are reported rather than wrapped.
+var converters = map[string]string{
+	"int":     "AsInt",
+	"int8":    "AsInt8",
+	"int16":   "AsInt16",
+	"int32":   "AsInt32",
+	"rune":    "AsInt32",
+	"uint":    "AsUint",
+	"uint8":   "AsUint8",
+	"byte":    "AsUint8",
+	"uint16":  "AsUint16",
+	"uint32":  "AsUint32",
+	"uint64":  "AsUint64",
+	"float32": "AsFloat32",
+}
+
+// bitSizes holds the size of the numeric types narrower than 64 bits, used to
+// check that a default value fits in its field.
+var bitSizes = map[string]int{
+	"int8":    8,
+	"int16":   16,
+	"int32":   32,
+	"rune":    32,
+	"uint8":   8,
+	"byte":    8,
+	"uint16":  16,
+	"uint32":  32,
+	"float32": 32,
+}
+
+var builtins = map[string]kind{
+	"string":  kindString,
+	"bool":    kindBoolean,
+	"int":     kindInteger,
+	"int8":    kindInteger,
+	"int16":   kindInteger,
+	"int32":   kindInteger,
+	"int64":   kindInteger,
+	"uint":    kindInteger,
+	"uint8":   kindInteger,
+	"uint16":  kindInteger,
+	"uint32":  kindInteger,
+	"uint64":  kindInteger,
+	"byte":    kindInteger,
+	"rune":    kindInteger,
+	"float32": kindNumber,
+	"float64": kindNumber,
+}
+
+type generator struct {
+	pkg     string
+	specs   map[string]*ast.TypeSpec
+	queue   []string
+	queued  map[string]bool
+	imports map[string]bool
+	buf     bytes.Buffer
+}
+
+// Generate parses the Go package in dir and returns the formatted source of
+// the unpack functions for the named types and every struct type they
+// reference.
+//
+// If no types are named, the types marked with a //maputil:unpack comment are
+// used.
+func Generate(dir string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	g := &generator{
+		specs:   map[string]*ast.TypeSpec{},
+		queued:  map[string]bool{},
+		imports: map[string]bool{},
+	}
+	var marked []string
+	for _, pkg := range pkgs {
+		g.pkg = pkg.Name
+		files := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			files = append(files, name)
+		}
+		sort.Strings(files)
+		for _, name := range files {
+			marked = append(marked, g.collect(pkg.Files[name])...)
+		}
+	}
+
+	if len(names) == 0 {
+		names = marked
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no types selected; use -type or mark types with %s", directive)
+	}
+	for _, name := range names {
+		if err := g.enqueue(name); err != nil {
+			return nil, err
+		}
+	}
+	return g.generate()
+}
+
+// collect records the type declarations of the file, returning the names of
+// those marked with the generation directive.
+func (g *generator) collect(f *ast.File) []string {
+	var marked []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			g.specs[ts.Name.Name] = ts
+			doc := ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			if hasDirective(doc) {
+				marked = append(marked, ts.Name.Name)
+			}
+		}
+	}
+	return marked
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) enqueue(name string) error {
+	ts, ok := g.specs[name]
+	if !ok {
+		return fmt.Errorf("type %s not found", name)
+	}
+	if _, ok := ts.Type.(*ast.StructType); !ok {
+		return fmt.Errorf("type %s is not a struct", name)
+	}
+	if !g.queued[name] {
+		g.queued[name] = true
+		g.queue = append(g.queue, name)
+	}
+	return nil
+}
+
+func (g *generator) generate() ([]byte, error) {
+	body := &g.buf
+	for i := 0; i < len(g.queue); i++ {
+		name := g.queue[i]
+		st := g.specs[name].Type.(*ast.StructType)
+		fmt.Fprintf(body, "\n// Unpack%s unpacks %s %s from the map, ", name, article(name), name)
+		fmt.Fprintf(body, "sending any errors to the given\n// context.\n")
+		fmt.Fprintf(body, "func Unpack%s(ctx *errctx.Context, m map[string]interface{}) %s {\n", name, name)
+		fmt.Fprintf(body, "v := %s{}\n", name)
+		if err := g.fields(name, st, "v."); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(body, "return v\n}\n")
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by maputil-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\nimport (\n", g.pkg)
+	g.imports["github.com/tvarney/maputil/errctx"] = true
+	g.imports["github.com/tvarney/maputil/unpack"] = true
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	for _, imp := range imports {
+		fmt.Fprintf(out, "%q\n", imp)
+	}
+	fmt.Fprintf(out, ")\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+func (g *generator) fields(typeName string, st *ast.StructType, prefix string) error {
+	for _, f := range st.Fields.List {
+		tag := ""
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return err
+			}
+			tag = reflect.StructTag(raw).Get(tags.Name)
+		}
+		if tag == "-" {
+			continue
+		}
+		key, opts, err := tags.Parse(tag)
+		if err != nil {
+			return fmt.Errorf("invalid %s tag on %s: %v", tags.Name, typeName, err)
+		}
+
+		if len(f.Names) == 0 {
+			// Embedded fields without a key name are promoted into the parent.
+			id, ok := f.Type.(*ast.Ident)
+			if !ok || key != "" {
+				return fmt.Errorf("unsupported embedded field %s in %s", types.ExprString(f.Type), typeName)
+			}
+			ts, ok := g.specs[id.Name]
+			if !ok {
+				return fmt.Errorf("unsupported embedded field %s in %s", id.Name, typeName)
+			}
+			est, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return fmt.Errorf("unsupported embedded field %s in %s", id.Name, typeName)
+			}
+			if err := g.fields(typeName, est, prefix+id.Name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ref, err := g.resolve(f.Type)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %v", typeName, f.Names[0].Name, err)
+		}
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			k := key
+			if k == "" {
+				k = n.Name
+			}
+			if err := g.field(prefix+n.Name, k, opts, ref); err != nil {
+				return fmt.Errorf("field %s.%s: %v", typeName, n.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolve converts a field type expression to a typeRef.
+func (g *generator) resolve(expr ast.Expr) (*typeRef, error) {
+	name := types.ExprString(expr)
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if k, ok := builtins[t.Name]; ok {
+			return &typeRef{kind: k, name: name, builtin: t.Name}, nil
+		}
+		ts, ok := g.specs[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s", name)
+		}
+		if _, ok := ts.Type.(*ast.StructType); ok {
+			return &typeRef{kind: kindStruct, name: name}, nil
+		}
+		ref, err := g.resolve(ts.Type)
+		if err != nil {
+			return nil, err
+		}
+		r := *ref
+		r.name = name
+		return &r, nil
+	case *ast.StarExpr:
+		elem, err := g.resolve(t.X)
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{kind: kindPointer, name: name, elem: elem}, nil
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return nil, fmt.Errorf("unsupported type %s", name)
+		}
+		if isEmptyInterface(t.Elt) {
+			return &typeRef{kind: kindArray, name: name}, nil
+		}
+		elem, err := g.resolve(t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{kind: kindSlice, name: name, elem: elem}, nil
+	case *ast.MapType:
+		if k, ok := t.Key.(*ast.Ident); ok && k.Name == "string" && isEmptyInterface(t.Value) {
+			return &typeRef{kind: kindObject, name: name}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %s", name)
+}
+
+func isEmptyInterface(expr ast.Expr) bool {
+	it, ok := expr.(*ast.InterfaceType)
+	return ok && len(it.Methods.List) == 0
+}
+
+// field writes the statements which unpack a single field.
+func (g *generator) field(target, key string, opts tags.Options, ref *typeRef) error {
+	w := &g.buf
+	mode := "Optional"
+	if opts.Required {
+		mode = "Require"
+	}
+	if len(opts.Enum) > 0 && !isStringOrStrings(ref) {
+		return fmt.Errorf("enum option requires a string type")
+	}
+	if opts.HasDefault && !isScalar(ref) && !(ref.kind == kindPointer && isScalar(ref.elem)) {
+		return fmt.Errorf("default option requires a scalar type")
+	}
+
+	switch ref.kind {
+	case kindString, kindBoolean, kindInteger, kindNumber:
+		if isConverted(ref) {
+			return g.convertedField(target, key, mode, opts, ref, false)
+		}
+		call, err := scalarCall(mode, key, opts, ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s = %s\n", target, call)
+	case kindObject:
+		fmt.Fprintf(w, "%s = unpack.%sObject(ctx, m, %q%s)\n", target, mode, key, optionalNil(mode))
+	case kindArray:
+		fmt.Fprintf(w, "%s = unpack.%sArray(ctx, m, %q%s)\n", target, mode, key, optionalNil(mode))
+	case kindStruct:
+		g.queueStruct(ref)
+		g.objectFunc(mode, key)
+		fmt.Fprintf(w, "%s = Unpack%s(ctx, o)\n})\n", target, ref.name)
+	case kindPointer:
+		return g.pointerField(target, key, mode, opts, ref.elem)
+	case kindSlice:
+		return g.sliceField(target, key, mode, opts, ref)
+	}
+	return nil
+}
+
+func (g *generator) pointerField(target, key, mode string, opts tags.Options, elem *typeRef) error {
+	w := &g.buf
+	switch {
+	case elem.kind == kindStruct:
+		g.queueStruct(elem)
+		g.objectFunc(mode, key)
+		fmt.Fprintf(w, "r := Unpack%s(ctx, o)\n%s = &r\n})\n", elem.name, target)
+	case isScalar(elem) && isConverted(elem):
+		return g.convertedField(target, key, mode, opts, elem, true)
+	case isScalar(elem):
+		call, err := scalarCall("Require", key, opts, elem)
+		if err != nil {
+			return err
+		}
+		if mode == "Require" {
+			fmt.Fprintf(w, "{\nx := %s\n%s = &x\n}\n", call, target)
+			return nil
+		}
+		if opts.HasDefault {
+			call, err = scalarCall("Optional", key, opts, elem)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "{\nx := %s\n%s = &x\n}\n", call, target)
+			return nil
+		}
+		fmt.Fprintf(w, "if _, ok := m[%q]; ok {\nx := %s\n%s = &x\n}\n", key, call, target)
+		fmt.Fprintf(w, "ctx.UseKey(m, %q)\n", key)
+	default:
+		return fmt.Errorf("unsupported pointer type *%s", elem.name)
+	}
+	return nil
+}
+
+// objectFunc writes the opening of a call to the ObjectFunc accessor of the
+// mode, leaving the body of the function to the caller.
+func (g *generator) objectFunc(mode, key string) {
+	fmt.Fprintf(&g.buf, "unpack.%sObjectFunc(ctx, m, %q, ", mode, key)
+	fmt.Fprintf(&g.buf, "func(ctx *errctx.Context, o map[string]interface{}) {\n")
+}
+
+// convertedField writes the statements which unpack a scalar whose type has no
+// accessor of its own, converting the value with the matching converter.
+func (g *generator) convertedField(target, key, mode string, opts tags.Options, ref *typeRef, pointer bool) error {
+	w := &g.buf
+	if opts.HasDefault {
+		dv, err := defaultLiteral(ref, opts.Default)
+		if err != nil {
+			return err
+		}
+		if pointer {
+			fmt.Fprintf(w, "{\nx := %s(%s)\n%s = &x\n}\n", ref.name, dv, target)
+		} else {
+			fmt.Fprintf(w, "%s = %s\n", target, dv)
+		}
+	}
+
+	fmt.Fprintf(w, "if e, ok := m[%q]; ok {\n", key)
+	fmt.Fprintf(w, "x, err := unpack.%s(ctx, e)\nif err == nil {\n", converters[ref.builtin])
+	switch {
+	case !pointer:
+		fmt.Fprintf(w, "%s = %s\n", target, convertElem("x", ref))
+	case ref.name == ref.builtin:
+		fmt.Fprintf(w, "%s = &x\n", target)
+	default:
+		fmt.Fprintf(w, "y := %s\n%s = &y\n", convertElem("x", ref), target)
+	}
+	fmt.Fprintf(w, "}\nctx.ErrorWithKey(err, %q)\n}", key)
+	if mode == "Require" {
+		g.imports["github.com/tvarney/maputil"] = true
+		fmt.Fprintf(w, " else {\nctx.ErrorWithKey(maputil.MissingRequiredValueError{Key: %q}, %q)\n}", key, key)
+	}
+	fmt.Fprintf(w, "\nctx.UseKey(m, %q)\n", key)
+	return nil
+}
+
+// sliceField writes the statements which unpack a slice, converting each
+// element in turn. As with Decode, elements which can not be converted are
+// reported and left as the zero value, so that the slice has the same length
+// as the array in the map.
+func (g *generator) sliceField(target, key, mode string, opts tags.Options, ref *typeRef) error {
+	w := &g.buf
+	elem := ref.elem
+	var conv, value string
+	switch {
+	case isScalar(elem):
+		conv, value = "x, err := "+g.converter(elem)+"\n", convertElem("x", elem)
+		if len(opts.Enum) > 0 {
+			g.imports["github.com/tvarney/maputil"] = true
+			conv += "if err == nil {\nerr = maputil.CheckEnum(x, " + enumLiteral(opts.Enum) + ")\n}\n"
+		}
+	case elem.kind == kindObject:
+		g.imports["github.com/tvarney/maputil"] = true
+		conv, value = "x, err := maputil.AsObject(e)\n", "x"
+	case elem.kind == kindStruct || (elem.kind == kindPointer && elem.elem.kind == kindStruct):
+		st, v := elem, "Unpack"+elem.name+"(ctx, o)"
+		if elem.kind == kindPointer {
+			st = elem.elem
+			v = "func() *" + st.name + " {\nr := Unpack" + st.name + "(ctx, o)\nreturn &r\n}()"
+		}
+		g.queueStruct(st)
+		g.imports["github.com/tvarney/maputil"] = true
+		conv, value = "o, err := maputil.AsObject(e)\n", v
+	default:
+		return fmt.Errorf("unsupported slice type []%s", elem.name)
+	}
+
+	g.imports["github.com/tvarney/maputil/mpath"] = true
+	fmt.Fprintf(w, "if a := unpack.%sArray(ctx, m, %q%s); len(a) > 0 {\n", mode, key, optionalNil(mode))
+	fmt.Fprintf(w, "%s = make(%s, len(a))\n", target, ref.name)
+	fmt.Fprintf(w, "scope := ctx.Enter(mpath.Key(%q))\n", key)
+	fmt.Fprintf(w, "for i, e := range a {\nctx.WithIndex(i, func() {\n")
+	fmt.Fprintf(w, "%sif err != nil {\nctx.Error(err)\nreturn\n}\n", conv)
+	fmt.Fprintf(w, "%s[i] = %s\n", target, value)
+	fmt.Fprintf(w, "})\n}\nctx.Leave(scope)\n}\n")
+	return nil
+}
+
+// converter returns the expression which converts the array element e to the
+// scalar type of ref, or to its underlying type.
+func (g *generator) converter(ref *typeRef) string {
+	if fn, ok := converters[ref.builtin]; ok {
+		return "unpack." + fn + "(ctx, e)"
+	}
+	if ref.kind == kindString {
+		g.imports["github.com/tvarney/maputil"] = true
+		return "maputil.AsString(e)"
+	}
+	return "unpack.As" + scalars[ref.kind].fn + "(ctx, e)"
+}
+
+func (g *generator) queueStruct(ref *typeRef) {
+	if !g.queued[ref.name] {
+		g.queued[ref.name] = true
+		g.queue = append(g.queue, ref.name)
+	}
+}
+
+// scalarCall returns the expression which unpacks a scalar value, including
+// any conversion to the field type.
+func scalarCall(mode, key string, opts tags.Options, ref *typeRef) (string, error) {
+	s := scalars[ref.kind]
+	if sz, ok := sized[ref.builtin]; ok {
+		s.fn, s.base = sz.fn, sz.base
+	}
+	fn, args := s.fn, ""
+	if len(opts.Enum) > 0 {
+		fn, args = "StringEnum", ", "+enumLiteral(opts.Enum)
+	}
+	if mode == "Optional" {
+		dv := s.zero
+		if opts.HasDefault {
+			var err error
+			if dv, err = defaultLiteral(ref, opts.Default); err != nil {
+				return "", err
+			}
+		}
+		args += ", " + dv
+	}
+
+	call := fmt.Sprintf("unpack.%s%s(ctx, m, %q%s)", mode, fn, key, args)
+	if ref.name != s.base {
+		call = ref.name + "(" + call + ")"
+	}
+	return call, nil
+}
+
+// convertElem returns the expression which converts the variable x, holding
+// the result of a converter, to the type of ref.
+func convertElem(x string, ref *typeRef) string {
+	if ref.name == ref.builtin {
+		return x
+	}
+	return ref.name + "(" + x + ")"
+}
+
+// defaultLiteral returns the literal of a default value, which must fit in
+// the type of ref.
+func defaultLiteral(ref *typeRef, value string) (string, error) {
+	bits, ok := bitSizes[ref.builtin]
+	if !ok {
+		bits = 64
+	}
+	switch ref.kind {
+	case kindString:
+		return strconv.Quote(value), nil
+	case kindBoolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %v", value, err)
+		}
+		return strconv.FormatBool(b), nil
+	case kindInteger:
+		if strings.HasPrefix(ref.builtin, "uint") || ref.builtin == "byte" {
+			u, err := strconv.ParseUint(value, 0, bits)
+			if err != nil {
+				return "", fmt.Errorf("invalid default %q: %v", value, err)
+			}
+			return strconv.FormatUint(u, 10), nil
+		}
+		i, err := strconv.ParseInt(value, 0, bits)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %v", value, err)
+		}
+		return strconv.FormatInt(i, 10), nil
+	}
+	f, err := strconv.ParseFloat(value, bits)
+	if err != nil {
+		return "", fmt.Errorf("invalid default %q: %v", value, err)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits), nil
+}
+
+// article returns the indefinite article for a type name.
+func article(name string) string {
+	if name != "" && strings.ContainsRune("AEIOUaeiou", rune(name[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+func enumLiteral(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+func optionalNil(mode string) string {
+	if mode == "Optional" {
+		return ", nil"
+	}
+	return ""
+}
+
+func isScalar(ref *typeRef) bool {
+	_, ok := scalars[ref.kind]
+	return ok
+}
+
+// isConverted returns true if the scalar type has no accessor of its own, and
+// is unpacked with its converter instead.
+func isConverted(ref *typeRef) bool {
+	_, ok := sized[ref.builtin]
+	return !ok && converters[ref.builtin] != ""
+}
+
+func isStringOrStrings(ref *typeRef) bool {
+	switch ref.kind {
+	case kindString:
+		return true
+	case kindPointer, kindSlice:
+		return ref.elem.kind == kindString
+	}
+	return false
+}
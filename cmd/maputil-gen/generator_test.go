@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "maputil-gen")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0o644))
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	t.Run("Golden", func(t *testing.T) {
+		t.Parallel()
+		expected, err := ioutil.ReadFile(filepath.Join("internal", "example", "example_unpack.go"))
+		require.NoError(t, err)
+		src, err := Generate(filepath.Join("internal", "example"), []string{"Config"})
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(src), "generated code is stale; run go generate")
+	})
+	t.Run("Directive", func(t *testing.T) {
+		t.Parallel()
+		dir := writePackage(t, `package p
+
+// A is marked.
+//maputil:unpack
+type A struct {
+	X int64 `+"`map:\"x\"`"+`
+}
+
+// B is not marked.
+type B struct {
+	Y string
+}
+`)
+		src, err := Generate(dir, nil)
+		require.NoError(t, err)
+		require.Contains(t, string(src), "func UnpackA(")
+		require.Contains(t, string(src), "// UnpackA unpacks an A from the map")
+		require.Contains(t, string(src), `v.X = unpack.OptionalInteger(ctx, m, "x", 0)`)
+		require.NotContains(t, string(src), "func UnpackB(")
+	})
+	t.Run("Converted", func(t *testing.T) {
+		t.Parallel()
+		dir := writePackage(t, `package p
+
+type Count int
+
+// A has fields without an accessor of their own width.
+type A struct {
+	N  int      `+"`map:\"n,required\"`"+`
+	C  *Count   `+"`map:\"c\"`"+`
+	F  float32  `+"`map:\"f,default=1.1\"`"+`
+	Ls []uint8  `+"`map:\"ls\"`"+`
+}
+`)
+		src, err := Generate(dir, []string{"A"})
+		require.NoError(t, err)
+		require.Contains(t, string(src), `ctx.ErrorWithKey(maputil.MissingRequiredValueError{Key: "n"}, "n")`)
+		require.Contains(t, string(src), "y := Count(x)\n")
+		require.Contains(t, string(src), "v.F = 1.1\n")
+		require.Contains(t, string(src), "x, err := unpack.AsUint8(ctx, e)\n")
+		require.NotContains(t, string(src), "int(")
+	})
+	t.Run("NoTypes", func(t *testing.T) {
+		t.Parallel()
+		dir := writePackage(t, "package p\n\ntype A struct{}\n")
+		_, err := Generate(dir, nil)
+		require.Error(t, err)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		cases := map[string]string{
+			"NotFound":        "type Other struct{}",
+			"NotStruct":       "type A int",
+			"Unsupported":     "type A struct { C chan int }",
+			"Interface":       "type A struct { I interface{} }",
+			"BadTag":          "type A struct { X int `map:\"x,bogus\"` }",
+			"BadDefault":      "type A struct { X int `map:\"x,default=ten\"` }",
+			"DefaultOverflow": "type A struct { X uint8 `map:\"x,default=300\"` }",
+			"DefaultNegative": "type A struct { X uint `map:\"x,default=-1\"` }",
+			"EnumNotString":   "type A struct { X int `map:\"x,enum=a|b\"` }",
+			"DefaultNotValue": "type A struct { X []int `map:\"x,default=1\"` }",
+			"ForeignType":     "type A struct { X time.Duration }",
+		}
+		for name, decl := range cases {
+			decl := decl
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				dir := writePackage(t, "package p\n\n"+decl+"\n")
+				_, err := Generate(dir, []string{"A"})
+				require.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,46 @@
+// Package example holds types used to test the code generated by
+// maputil-gen.
+package example
+
+//go:generate go run github.com/tvarney/maputil/cmd/maputil-gen -type=Config
+
+// Mode is the operating mode of a server.
+type Mode string
+
+// Base holds fields shared by several types.
+type Base struct {
+	ID string `map:"id,required"`
+}
+
+// TLS holds TLS settings.
+type TLS struct {
+	Enabled bool   `map:"enabled"`
+	Cert    string `map:"cert,default=cert.pem"`
+}
+
+// Server describes a single server.
+type Server struct {
+	Base
+	Host    string   `map:"host,default=localhost"`
+	Port    uint16   `map:"port,required"`
+	Mode    Mode     `map:"mode,enum=active|passive,default=active"`
+	Weight  float32  `map:"weight,default=1.5"`
+	Tags    []string `map:"tags,enum=a|b|c"`
+	Ports   []int    `map:"ports"`
+	Levels  []uint8  `map:"levels"`
+	TLS     *TLS     `map:"tls"`
+	Retries *int     `map:"retries"`
+	Ignored string   `map:"-"`
+	private int
+}
+
+// Config is the root configuration type.
+type Config struct {
+	Name     string                 `map:"name,required"`
+	Debug    bool                   `map:"debug"`
+	Servers  []Server               `map:"servers,required"`
+	Backup   *Server                `map:"backup"`
+	Primary  TLS                    `map:"primary,required"`
+	Metadata map[string]interface{} `map:"metadata"`
+	Extra    []interface{}          `map:"extra"`
+}
@@ -0,0 +1,197 @@
+package example_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/cmd/maputil-gen/internal/example"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestUnpackConfig(t *testing.T) {
+	t.Parallel()
+	t.Run("Good", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"name": "test",
+			"servers": []interface{}{
+				map[string]interface{}{
+					"id":      "one",
+					"port":    80,
+					"tags":    []interface{}{"a", "c"},
+					"ports":   []interface{}{1, 2},
+					"tls":     map[string]interface{}{"enabled": true},
+					"retries": 3,
+				},
+			},
+			"primary":  map[string]interface{}{"cert": "p.pem"},
+			"metadata": map[string]interface{}{"k": "v"},
+		}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		cfg := example.UnpackConfig(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+
+		retries := 3
+		expected := example.Config{
+			Name: "test",
+			Servers: []example.Server{{
+				Base:    example.Base{ID: "one"},
+				Host:    "localhost",
+				Port:    80,
+				Mode:    "active",
+				Weight:  1.5,
+				Tags:    []string{"a", "c"},
+				Ports:   []int{1, 2},
+				TLS:     &example.TLS{Enabled: true, Cert: "cert.pem"},
+				Retries: &retries,
+			}},
+			Primary:  example.TLS{Cert: "p.pem"},
+			Metadata: map[string]interface{}{"k": "v"},
+		}
+		require.Equal(t, expected, cfg)
+
+		// The generated code must agree with the reflection based decoder.
+		decoded := example.Config{}
+		unpack.Decode(ctx, m, &decoded)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, expected, decoded)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"port": "80", "mode": "other"},
+				"bad",
+			},
+			"backup": map[string]interface{}{
+				"id":     "b",
+				"port":   70000,
+				"weight": 1e39,
+				"levels": []interface{}{1, 300},
+				"tls":    1,
+			},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		example.UnpackConfig(ctx, m)
+		require.Equal(t, []string{
+			`name: missing required value "name"`,
+			`servers[0].id: missing required value "id"`,
+			`servers[0].port: invalid type string; expected integer`,
+			`servers[0].mode: invalid value "other"; expected "active" or "passive"`,
+			`servers[1]: invalid type string; expected object`,
+			`backup.port: invalid value 70000; overflows uint16`,
+			`backup.weight: invalid value 1e+39; overflows float32`,
+			`backup.levels[1]: invalid value 300; overflows uint8`,
+			`backup.tls: invalid type integer; expected object`,
+			`primary: missing required value "primary"`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("BadElements", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"name": "test",
+			"servers": []interface{}{
+				map[string]interface{}{
+					"id":     "one",
+					"port":   80,
+					"tags":   []interface{}{"a", "x", "c"},
+					"ports":  []interface{}{1, "2", 3},
+					"levels": []interface{}{300, 4},
+				},
+				"bad",
+			},
+			"primary": map[string]interface{}{},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		cfg := example.UnpackConfig(ctx, m)
+		errs := []string{
+			`servers[0].tags[1]: invalid value "x"; expected one of "a", "b", or "c"`,
+			`servers[0].ports[1]: invalid type string; expected integer`,
+			`servers[0].levels[0]: invalid value 300; overflows uint8`,
+			`servers[1]: invalid type string; expected object`,
+		}
+		require.Equal(t, errs, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+
+		// Elements which can not be converted are left as zero values.
+		expected := example.Config{
+			Name: "test",
+			Servers: []example.Server{{
+				Base:   example.Base{ID: "one"},
+				Host:   "localhost",
+				Port:   80,
+				Mode:   "active",
+				Weight: 1.5,
+				Tags:   []string{"a", "", "c"},
+				Ports:  []int{1, 0, 3},
+				Levels: []uint8{0, 4},
+			}, {}},
+			Primary: example.TLS{Cert: "cert.pem"},
+		}
+		require.Equal(t, expected, cfg)
+
+		sb.Reset()
+		decoded := example.Config{}
+		unpack.Decode(ctx, m, &decoded)
+		require.Equal(t, expected, decoded)
+		require.Equal(t, errs, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Lenient", func(t *testing.T) {
+		t.Parallel()
+		server := map[string]interface{}{
+			"id":      "one",
+			"port":    "8080",
+			"weight":  "2.5",
+			"ports":   []interface{}{"1", 2},
+			"levels":  []interface{}{"3"},
+			"retries": "4",
+		}
+		m := map[string]interface{}{
+			"name":    "test",
+			"servers": []interface{}{server},
+			"primary": map[string]interface{}{"enabled": "true"},
+		}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Lenient = true
+		cfg := example.UnpackConfig(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+
+		retries := 4
+		expected := example.Config{
+			Name: "test",
+			Servers: []example.Server{{
+				Base:    example.Base{ID: "one"},
+				Host:    "localhost",
+				Port:    8080,
+				Mode:    "active",
+				Weight:  2.5,
+				Ports:   []int{1, 2},
+				Levels:  []uint8{3},
+				Retries: &retries,
+			}},
+			Primary: example.TLS{Enabled: true, Cert: "cert.pem"},
+		}
+		require.Equal(t, expected, cfg)
+
+		decoded := example.Config{}
+		unpack.Decode(ctx, m, &decoded)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, expected, decoded)
+	})
+	t.Run("UnknownKeys", func(t *testing.T) {
+		t.Parallel()
+		tls := map[string]interface{}{"enabled": true, "crt": "x"}
+		m := map[string]interface{}{"name": "n", "servers": []interface{}{}, "primary": tls}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Keys = errctx.NewKeyTracker()
+		example.UnpackConfig(ctx, m)
+		unpack.CheckUnknownKeys(ctx, m)
+		unpack.CheckUnknownKeys(ctx, tls)
+		require.Equal(t, "crt: unknown key \"crt\"; did you mean \"cert\"?\n", sb.String())
+	})
+}
@@ -0,0 +1,132 @@
+// Code generated by maputil-gen; DO NOT EDIT.
+
+package example
+
+import (
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+	"github.com/tvarney/maputil/unpack"
+)
+
+// UnpackConfig unpacks a Config from the map, sending any errors to the given
+// context.
+func UnpackConfig(ctx *errctx.Context, m map[string]interface{}) Config {
+	v := Config{}
+	v.Name = unpack.RequireString(ctx, m, "name")
+	v.Debug = unpack.OptionalBoolean(ctx, m, "debug", false)
+	if a := unpack.RequireArray(ctx, m, "servers"); len(a) > 0 {
+		v.Servers = make([]Server, len(a))
+		scope := ctx.Enter(mpath.Key("servers"))
+		for i, e := range a {
+			ctx.WithIndex(i, func() {
+				o, err := maputil.AsObject(e)
+				if err != nil {
+					ctx.Error(err)
+					return
+				}
+				v.Servers[i] = UnpackServer(ctx, o)
+			})
+		}
+		ctx.Leave(scope)
+	}
+	unpack.OptionalObjectFunc(ctx, m, "backup", func(ctx *errctx.Context, o map[string]interface{}) {
+		r := UnpackServer(ctx, o)
+		v.Backup = &r
+	})
+	unpack.RequireObjectFunc(ctx, m, "primary", func(ctx *errctx.Context, o map[string]interface{}) {
+		v.Primary = UnpackTLS(ctx, o)
+	})
+	v.Metadata = unpack.OptionalObject(ctx, m, "metadata", nil)
+	v.Extra = unpack.OptionalArray(ctx, m, "extra", nil)
+	return v
+}
+
+// UnpackServer unpacks a Server from the map, sending any errors to the given
+// context.
+func UnpackServer(ctx *errctx.Context, m map[string]interface{}) Server {
+	v := Server{}
+	v.Base.ID = unpack.RequireString(ctx, m, "id")
+	v.Host = unpack.OptionalString(ctx, m, "host", "localhost")
+	v.Port = unpack.RequireUint16(ctx, m, "port")
+	v.Mode = Mode(unpack.OptionalStringEnum(ctx, m, "mode", []string{"active", "passive"}, "active"))
+	v.Weight = 1.5
+	if e, ok := m["weight"]; ok {
+		x, err := unpack.AsFloat32(ctx, e)
+		if err == nil {
+			v.Weight = x
+		}
+		ctx.ErrorWithKey(err, "weight")
+	}
+	ctx.UseKey(m, "weight")
+	if a := unpack.OptionalArray(ctx, m, "tags", nil); len(a) > 0 {
+		v.Tags = make([]string, len(a))
+		scope := ctx.Enter(mpath.Key("tags"))
+		for i, e := range a {
+			ctx.WithIndex(i, func() {
+				x, err := maputil.AsString(e)
+				if err == nil {
+					err = maputil.CheckEnum(x, []string{"a", "b", "c"})
+				}
+				if err != nil {
+					ctx.Error(err)
+					return
+				}
+				v.Tags[i] = x
+			})
+		}
+		ctx.Leave(scope)
+	}
+	if a := unpack.OptionalArray(ctx, m, "ports", nil); len(a) > 0 {
+		v.Ports = make([]int, len(a))
+		scope := ctx.Enter(mpath.Key("ports"))
+		for i, e := range a {
+			ctx.WithIndex(i, func() {
+				x, err := unpack.AsInt(ctx, e)
+				if err != nil {
+					ctx.Error(err)
+					return
+				}
+				v.Ports[i] = x
+			})
+		}
+		ctx.Leave(scope)
+	}
+	if a := unpack.OptionalArray(ctx, m, "levels", nil); len(a) > 0 {
+		v.Levels = make([]uint8, len(a))
+		scope := ctx.Enter(mpath.Key("levels"))
+		for i, e := range a {
+			ctx.WithIndex(i, func() {
+				x, err := unpack.AsUint8(ctx, e)
+				if err != nil {
+					ctx.Error(err)
+					return
+				}
+				v.Levels[i] = x
+			})
+		}
+		ctx.Leave(scope)
+	}
+	unpack.OptionalObjectFunc(ctx, m, "tls", func(ctx *errctx.Context, o map[string]interface{}) {
+		r := UnpackTLS(ctx, o)
+		v.TLS = &r
+	})
+	if e, ok := m["retries"]; ok {
+		x, err := unpack.AsInt(ctx, e)
+		if err == nil {
+			v.Retries = &x
+		}
+		ctx.ErrorWithKey(err, "retries")
+	}
+	ctx.UseKey(m, "retries")
+	return v
+}
+
+// UnpackTLS unpacks a TLS from the map, sending any errors to the given
+// context.
+func UnpackTLS(ctx *errctx.Context, m map[string]interface{}) TLS {
+	v := TLS{}
+	v.Enabled = unpack.OptionalBoolean(ctx, m, "enabled", false)
+	v.Cert = unpack.OptionalString(ctx, m, "cert", "cert.pem")
+	return v
+}
@@ -0,0 +1,77 @@
+// Command maputil-gen generates static unpack functions for Go struct types.
+//
+// For each selected struct type T, maputil-gen writes a function
+//
+//	func UnpackT(ctx *errctx.Context, m map[string]interface{}) T
+//
+// built on the Require* and Optional* functions of the unpack package. Fields
+// are mapped using the same `map` struct tags understood by unpack.Decode, so
+// the generated code behaves like Decode without the cost of reflection: the
+// same errors are reported at the same paths, and elements of a slice which
+// can not be converted are left as zero values.
+//
+// Types are selected with the -type flag, or by placing a //maputil:unpack
+// comment on the type declaration. Struct types referenced by a selected type
+// are generated as well. The typical usage is a go:generate directive:
+//
+//	//go:generate maputil-gen -type=Config
+//
+// By default the output is written to <file>_unpack.go, where <file> is the
+// file containing the go:generate directive. When not run by go generate, the
+// output defaults to unpack_gen.go in the package directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "",
+		"comma-separated list of type names; defaults to types marked with "+directive)
+	output := flag.String("output", "",
+		"output file name; defaults to <file>_unpack.go under go generate, or unpack_gen.go otherwise")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: maputil-gen [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	src, err := Generate(dir, types)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "maputil-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	name := *output
+	if name == "" {
+		name = "unpack_gen.go"
+		if file := os.Getenv("GOFILE"); file != "" {
+			name = strings.TrimSuffix(file, ".go") + "_unpack.go"
+		}
+		name = filepath.Join(dir, name)
+	}
+	if err := ioutil.WriteFile(name, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "maputil-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
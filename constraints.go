@@ -0,0 +1,189 @@
+package maputil
+
+import (
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Bound tags mark the minimum or maximum of a range as not specified, or as
+// exclusive.
+const (
+	BoundTagInclusive    = 0
+	BoundTagNoMin        = 1 << 0
+	BoundTagNoMax        = 1 << 1
+	BoundTagExclusiveMin = 1 << 2
+	BoundTagExclusiveMax = 1 << 3
+)
+
+// IntegerRange is a range of allowed integer values.
+type IntegerRange struct {
+	Min int64
+	Max int64
+	Tag int
+}
+
+// IntegerBetween returns a new IntegerRange including both min and max.
+func IntegerBetween(min, max int64) IntegerRange {
+	return IntegerRange{Min: min, Max: max, Tag: BoundTagInclusive}
+}
+
+// IntegerAtLeast returns a new IntegerRange with only an inclusive minimum.
+func IntegerAtLeast(min int64) IntegerRange {
+	return IntegerRange{Min: min, Tag: BoundTagNoMax}
+}
+
+// IntegerAtMost returns a new IntegerRange with only an inclusive maximum.
+func IntegerAtMost(max int64) IntegerRange {
+	return IntegerRange{Max: max, Tag: BoundTagNoMin}
+}
+
+// ExclusiveMin returns a copy of the range with an exclusive minimum.
+func (r IntegerRange) ExclusiveMin() IntegerRange {
+	r.Tag |= BoundTagExclusiveMin
+	return r
+}
+
+// ExclusiveMax returns a copy of the range with an exclusive maximum.
+func (r IntegerRange) ExclusiveMax() IntegerRange {
+	r.Tag |= BoundTagExclusiveMax
+	return r
+}
+
+// Check returns an OutOfRangeError if the value is not within the range.
+func (r IntegerRange) Check(i int64) error {
+	value := strconv.FormatInt(i, 10)
+	if r.Tag&BoundTagNoMin == 0 {
+		if r.Tag&BoundTagExclusiveMin != 0 && i <= r.Min {
+			return OutOfRangeError{Value: value, Constraint: "exclusive minimum", Limit: strconv.FormatInt(r.Min, 10)}
+		}
+		if i < r.Min {
+			return OutOfRangeError{Value: value, Constraint: "minimum", Limit: strconv.FormatInt(r.Min, 10)}
+		}
+	}
+	if r.Tag&BoundTagNoMax == 0 {
+		if r.Tag&BoundTagExclusiveMax != 0 && i >= r.Max {
+			return OutOfRangeError{Value: value, Constraint: "exclusive maximum", Limit: strconv.FormatInt(r.Max, 10)}
+		}
+		if i > r.Max {
+			return OutOfRangeError{Value: value, Constraint: "maximum", Limit: strconv.FormatInt(r.Max, 10)}
+		}
+	}
+	return nil
+}
+
+// NumberRange is a range of allowed numeric values.
+type NumberRange struct {
+	Min float64
+	Max float64
+	Tag int
+}
+
+// NumberBetween returns a new NumberRange including both min and max.
+func NumberBetween(min, max float64) NumberRange {
+	return NumberRange{Min: min, Max: max, Tag: BoundTagInclusive}
+}
+
+// NumberAtLeast returns a new NumberRange with only an inclusive minimum.
+func NumberAtLeast(min float64) NumberRange {
+	return NumberRange{Min: min, Tag: BoundTagNoMax}
+}
+
+// NumberAtMost returns a new NumberRange with only an inclusive maximum.
+func NumberAtMost(max float64) NumberRange {
+	return NumberRange{Max: max, Tag: BoundTagNoMin}
+}
+
+// ExclusiveMin returns a copy of the range with an exclusive minimum.
+func (r NumberRange) ExclusiveMin() NumberRange {
+	r.Tag |= BoundTagExclusiveMin
+	return r
+}
+
+// ExclusiveMax returns a copy of the range with an exclusive maximum.
+func (r NumberRange) ExclusiveMax() NumberRange {
+	r.Tag |= BoundTagExclusiveMax
+	return r
+}
+
+// Check returns an OutOfRangeError if the value is not within the range.
+func (r NumberRange) Check(f float64) error {
+	value := formatNumber(f)
+	if r.Tag&BoundTagNoMin == 0 {
+		if r.Tag&BoundTagExclusiveMin != 0 && f <= r.Min {
+			return OutOfRangeError{Value: value, Constraint: "exclusive minimum", Limit: formatNumber(r.Min)}
+		}
+		if f < r.Min {
+			return OutOfRangeError{Value: value, Constraint: "minimum", Limit: formatNumber(r.Min)}
+		}
+	}
+	if r.Tag&BoundTagNoMax == 0 {
+		if r.Tag&BoundTagExclusiveMax != 0 && f >= r.Max {
+			return OutOfRangeError{Value: value, Constraint: "exclusive maximum", Limit: formatNumber(r.Max)}
+		}
+		if f > r.Max {
+			return OutOfRangeError{Value: value, Constraint: "maximum", Limit: formatNumber(r.Max)}
+		}
+	}
+	return nil
+}
+
+// LengthRange is a range of allowed lengths of a string or array.
+//
+// Lengths are always inclusive; the exclusive bound tags are ignored.
+type LengthRange struct {
+	Min int
+	Max int
+	Tag int
+}
+
+// LengthBetween returns a new LengthRange including both min and max.
+func LengthBetween(min, max int) LengthRange {
+	return LengthRange{Min: min, Max: max, Tag: BoundTagInclusive}
+}
+
+// LengthAtLeast returns a new LengthRange with only a minimum.
+func LengthAtLeast(min int) LengthRange {
+	return LengthRange{Min: min, Tag: BoundTagNoMax}
+}
+
+// LengthAtMost returns a new LengthRange with only a maximum.
+func LengthAtMost(max int) LengthRange {
+	return LengthRange{Max: max, Tag: BoundTagNoMin}
+}
+
+func (r LengthRange) check(n int) error {
+	value := "of length " + strconv.Itoa(n)
+	if r.Tag&BoundTagNoMin == 0 && n < r.Min {
+		return OutOfRangeError{Value: value, Constraint: "minimum length", Limit: strconv.Itoa(r.Min)}
+	}
+	if r.Tag&BoundTagNoMax == 0 && n > r.Max {
+		return OutOfRangeError{Value: value, Constraint: "maximum length", Limit: strconv.Itoa(r.Max)}
+	}
+	return nil
+}
+
+// CheckArrayLength returns an OutOfRangeError if the number of elements of the
+// array is not within the range.
+func CheckArrayLength(a []interface{}, r LengthRange) error {
+	return r.check(len(a))
+}
+
+// CheckStringLength returns an OutOfRangeError if the number of characters of
+// the string is not within the range.
+func CheckStringLength(s string, r LengthRange) error {
+	return r.check(utf8.RuneCountInString(s))
+}
+
+// CheckPattern returns a PatternError if the string does not match the
+// regular expression.
+func CheckPattern(s string, re *regexp.Regexp) error {
+	if re.MatchString(s) {
+		return nil
+	}
+	return PatternError{Value: s, Pattern: re.String()}
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,197 @@
+package maputil_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestIntegerRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		r     maputil.IntegerRange
+		value int64
+		err   string
+	}{
+		{"Between", maputil.IntegerBetween(1, 10), 5, ""},
+		{"BetweenMin", maputil.IntegerBetween(1, 10), 1, ""},
+		{"BetweenMax", maputil.IntegerBetween(1, 10), 10, ""},
+		{"BelowMin", maputil.IntegerBetween(1, 10), 0, "invalid value 0; minimum is 1"},
+		{"AboveMax", maputil.IntegerBetween(1, 10), 11, "invalid value 11; maximum is 10"},
+		{"ExclusiveMin", maputil.IntegerBetween(1, 10).ExclusiveMin(), 1, "invalid value 1; exclusive minimum is 1"},
+		{"ExclusiveMax", maputil.IntegerBetween(1, 10).ExclusiveMax(), 10, "invalid value 10; exclusive maximum is 10"},
+		{"AtLeast", maputil.IntegerAtLeast(1), 1 << 40, ""},
+		{"AtLeastInvalid", maputil.IntegerAtLeast(1), -1, "invalid value -1; minimum is 1"},
+		{"AtMost", maputil.IntegerAtMost(1), -1 << 40, ""},
+		{"AtMostInvalid", maputil.IntegerAtMost(1), 2, "invalid value 2; maximum is 1"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.r.Check(tc.value)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+		})
+	}
+}
+
+func TestNumberRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		r     maputil.NumberRange
+		value float64
+		err   string
+	}{
+		{"Between", maputil.NumberBetween(0, 1), 0.5, ""},
+		{"BelowMin", maputil.NumberBetween(0, 1), -0.5, "invalid value -0.5; minimum is 0"},
+		{"AboveMax", maputil.NumberBetween(0, 1), 1.5, "invalid value 1.5; maximum is 1"},
+		{"ExclusiveMin", maputil.NumberAtLeast(0).ExclusiveMin(), 0, "invalid value 0; exclusive minimum is 0"},
+		{"ExclusiveMax", maputil.NumberAtMost(1).ExclusiveMax(), 1, "invalid value 1; exclusive maximum is 1"},
+		{"ExclusiveValid", maputil.NumberBetween(0, 1).ExclusiveMin().ExclusiveMax(), 0.001, ""},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.r.Check(tc.value)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestCheckLength(t *testing.T) {
+	t.Parallel()
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, maputil.CheckStringLength("äöü", maputil.LengthBetween(3, 3)))
+		require.EqualError(t, maputil.CheckStringLength("", maputil.LengthAtLeast(1)),
+			"invalid value of length 0; minimum length is 1")
+		require.EqualError(t, maputil.CheckStringLength("abc", maputil.LengthAtMost(2)),
+			"invalid value of length 3; maximum length is 2")
+	})
+	t.Run("Array", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, maputil.CheckArrayLength(testArray, maputil.LengthBetween(1, 3)))
+		require.EqualError(t, maputil.CheckArrayLength(testArray, maputil.LengthAtLeast(4)),
+			"invalid value of length 3; minimum length is 4")
+		require.EqualError(t, maputil.CheckArrayLength(testArray, maputil.LengthAtMost(2)),
+			"invalid value of length 3; maximum length is 2")
+	})
+}
+
+func TestCheckPattern(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile("^[a-z]+$")
+	require.NoError(t, maputil.CheckPattern("abc", re))
+	err := maputil.CheckPattern("ABC", re)
+	require.EqualError(t, err, `invalid value "ABC"; expected to match "^[a-z]+$"`)
+	require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+}
+
+func TestGetIntegerRange(t *testing.T) {
+	t.Parallel()
+	r := maputil.IntegerBetween(1, 10)
+	m := map[string]interface{}{keyGood: 5, keyBad: testString, keyBadVal: 11}
+
+	i, ok, err := maputil.GetIntegerRange(m, keyGood, r)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(5), i)
+
+	i, ok, err = maputil.GetIntegerRange(m, keyMissing, r)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Zero(t, i)
+
+	_, ok, err = maputil.GetIntegerRange(m, keyBad, r)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+	require.True(t, ok)
+
+	i, ok, err = maputil.GetIntegerRange(m, keyBadVal, r)
+	require.EqualError(t, err, "invalid value 11; maximum is 10")
+	require.True(t, ok)
+	require.Equal(t, int64(11), i) // The value is still returned
+}
+
+func TestOptionalNumberRange(t *testing.T) {
+	t.Parallel()
+	r := maputil.NumberBetween(0, 1)
+	m := map[string]interface{}{keyGood: 0.5, keyBad: testString, keyBadVal: 2}
+	const dv = 0.25
+
+	n, err := maputil.OptionalNumberRange(m, keyGood, r, dv)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, n)
+
+	n, err = maputil.OptionalNumberRange(m, keyMissing, r, dv)
+	require.NoError(t, err)
+	require.Equal(t, dv, n)
+
+	n, err = maputil.OptionalNumberRange(m, keyBad, r, dv)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+	require.Equal(t, dv, n)
+
+	n, err = maputil.OptionalNumberRange(m, keyBadVal, r, dv)
+	require.EqualError(t, err, "invalid value 2; maximum is 1")
+	require.Equal(t, dv, n)
+}
+
+func TestRequireStringConstraints(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile("^[a-z]+$")
+	m := map[string]interface{}{keyGood: "abc", keyBad: testInteger, keyBadVal: "ABCD"}
+
+	s, err := maputil.RequireStringLength(m, keyGood, maputil.LengthAtMost(3))
+	require.NoError(t, err)
+	require.Equal(t, "abc", s)
+	s, err = maputil.RequireStringLength(m, keyBadVal, maputil.LengthAtMost(3))
+	require.EqualError(t, err, "invalid value of length 4; maximum length is 3")
+	require.Equal(t, "ABCD", s)
+	_, err = maputil.RequireStringLength(m, keyMissing, maputil.LengthAtMost(3))
+	require.True(t, errors.Is(err, maputil.ErrMissingRequiredValue))
+
+	s, err = maputil.RequireStringPattern(m, keyGood, re)
+	require.NoError(t, err)
+	require.Equal(t, "abc", s)
+	_, err = maputil.RequireStringPattern(m, keyBadVal, re)
+	require.EqualError(t, err, `invalid value "ABCD"; expected to match "^[a-z]+$"`)
+	_, err = maputil.RequireStringPattern(m, keyBad, re)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+}
+
+func TestArrayLength(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{keyGood: testArray, keyBad: testString}
+	r := maputil.LengthBetween(1, 2)
+
+	a, err := maputil.RequireArrayLength(m, keyGood, r)
+	require.EqualError(t, err, "invalid value of length 3; maximum length is 2")
+	require.Equal(t, testArray, a)
+
+	a, err = maputil.OptionalArrayLength(m, keyGood, r, nil)
+	require.Error(t, err)
+	require.Nil(t, a)
+
+	a, ok, err := maputil.GetArrayLength(m, keyGood, maputil.LengthAtLeast(3))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, testArray, a)
+
+	_, ok, err = maputil.GetArrayLength(m, keyBad, r)
+	require.True(t, ok)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+}
@@ -1,14 +1,53 @@
 package errctx
 
-import "github.com/tvarney/maputil/mpath"
+import (
+	"context"
+	"sync"
+
+	"github.com/tvarney/maputil/mpath"
+)
 
 // Context is an error context.
 type Context struct {
 	Path    *mpath.Path
 	Handler ErrorHandler
 
-	errCount int
-	lastErr  error
+	// Keys, if not nil, records the keys read from each object so that
+	// unexpected keys can be detected.
+	Keys *KeyTracker
+
+	// Lenient, if true, makes the unpack functions parse strings when a
+	// boolean, integer of any size, number, percentage or null is expected,
+	// including the elements of arrays, maps and tuples, for values read from
+	// sources such as environment variables which only hold strings.
+	Lenient bool
+
+	// Consume, if true, deletes each key from its object once it has been
+	// read, so that any keys left over after unpacking are the ones which
+	// were not expected.
+	Consume bool
+
+	errCount  int
+	lastErr   error
+	collected *ErrorCollector
+	consumed  []usedKey
+	cancel    *cancelState
+}
+
+// usedKey is a key read by a forked context which consumes keys. The key is
+// deleted when the fork is joined rather than when it is used, since the
+// object may be shared with other forks.
+type usedKey struct {
+	m   map[string]interface{}
+	key string
+}
+
+// cancelState holds the context.Context of a Context, shared between a context
+// and its forks so that cancellation is only reported once.
+type cancelState struct {
+	ctx      context.Context
+	mu       sync.Mutex
+	reported bool
 }
 
 // New returns a new context.
@@ -37,6 +76,131 @@ func New(handlers ...ErrorHandler) *Context {
 	}
 }
 
+// Scope is a token returned by Context.Enter which records the state of the
+// context path prior to entering a new scope.
+type Scope struct {
+	depth int
+}
+
+// Enter appends the given elements to the context path and returns a Scope
+// which restores the path when passed to Leave.
+//
+// The typical usage is to defer the call to Leave immediately:
+//
+//	defer ctx.Leave(ctx.Enter(mpath.Key("server")))
+func (ctx *Context) Enter(elems ...mpath.Element) Scope {
+	s := Scope{depth: len(ctx.Path.Elements)}
+	for _, e := range elems {
+		ctx.Path.Add(e)
+	}
+	return s
+}
+
+// Leave restores the context path to the state it was in when the given Scope
+// was created.
+//
+// Any elements added after the scope was entered are removed, even if they
+// were never popped.
+func (ctx *Context) Leave(s Scope) {
+	if n := len(ctx.Path.Elements) - s.depth; n > 0 {
+		ctx.Path.PopN(n)
+	}
+}
+
+// With calls fn with the given element appended to the context path,
+// restoring the path afterwards.
+func (ctx *Context) With(elem mpath.Element, fn func()) {
+	defer ctx.Leave(ctx.Enter(elem))
+	fn()
+}
+
+// WithKey calls fn with the given key appended to the context path, restoring
+// the path afterwards.
+func (ctx *Context) WithKey(key string, fn func()) {
+	ctx.With(mpath.Key(key), fn)
+}
+
+// WithIndex calls fn with the given index appended to the context path,
+// restoring the path afterwards.
+func (ctx *Context) WithIndex(idx int, fn func()) {
+	ctx.With(mpath.Index(idx), fn)
+}
+
+// Fork returns a child context which may be used in another goroutine.
+//
+// The child starts with a copy of the current path and the lenient and
+// consume settings, and shares the key tracker and attached context.Context of
+// this context. Errors handled by the child are held until the child is passed
+// to Join; the child must not be used once it has been joined.
+//
+// A child which consumes keys does not delete them as they are used, so that
+// several children may read the same objects at once; the keys are deleted
+// from their objects by Join instead.
+func (ctx *Context) Fork() *Context {
+	collected := &ErrorCollector{}
+	return &Context{
+		Path:      ctx.Path.Copy(),
+		Handler:   collected,
+		Keys:      ctx.Keys,
+		Lenient:   ctx.Lenient,
+		Consume:   ctx.Consume,
+		collected: collected,
+		cancel:    ctx.cancel,
+	}
+}
+
+// Join merges the errors of the given forked contexts into this context.
+//
+// Errors are sent to the handler of this context in the order the children
+// are given, and in the order each child handled them, so the result does not
+// depend on how the goroutines using the children were scheduled.
+//
+// Keys consumed by the children are deleted from their objects, or held until
+// this context is joined if it is itself a fork.
+//
+// Join panics if a child was not created by Fork, since the errors of such a
+// context have already been sent to its own handler and can not be merged.
+func (ctx *Context) Join(children ...*Context) {
+	for _, child := range children {
+		if child.collected == nil {
+			panic("errctx: joined a context which was not created by Fork")
+		}
+		if ctx.Handler != nil {
+			for _, e := range child.collected.Entries {
+				ctx.Handler.Add(e.Path, e.Err)
+			}
+		}
+		ctx.errCount += child.errCount
+		if child.lastErr != nil {
+			ctx.lastErr = child.lastErr
+		}
+		for _, u := range child.consumed {
+			ctx.consume(u.m, u.key)
+		}
+	}
+}
+
+// Parallel calls each function in its own goroutine with a forked context,
+// waits for all of them to return, then joins the forked contexts in the order
+// the functions were given.
+//
+// The functions may read the same objects, but must not modify them; keys
+// consumed by the forked contexts are only deleted once all of them return.
+func (ctx *Context) Parallel(fns ...func(ctx *Context)) {
+	children := make([]*Context, len(fns))
+	wg := sync.WaitGroup{}
+	wg.Add(len(fns))
+	for i, fn := range fns {
+		children[i] = ctx.Fork()
+		go func(child *Context, fn func(*Context)) {
+			defer wg.Done()
+			fn(child)
+		}(children[i], fn)
+	}
+	wg.Wait()
+	ctx.Join(children...)
+}
+
 // ErrorCount returns the total count of errors this context has handled.
 func (ctx *Context) ErrorCount() int {
 	return ctx.errCount
@@ -47,6 +211,84 @@ func (ctx *Context) LastError() error {
 	return ctx.lastErr
 }
 
+// SetContext attaches a context.Context to this context.
+//
+// Once the attached context is done, Canceled returns true and long-running
+// operations stop early.
+func (ctx *Context) SetContext(c context.Context) {
+	ctx.cancel = &cancelState{ctx: c}
+}
+
+// Context returns the attached context.Context, or context.Background if none
+// was attached.
+func (ctx *Context) Context() context.Context {
+	if ctx.cancel == nil {
+		return context.Background()
+	}
+	return ctx.cancel.ctx
+}
+
+// Canceled returns true if the attached context.Context is done.
+//
+// The first call to notice the cancellation reports a CanceledError at the
+// current path; subsequent calls, including those on forked contexts, only
+// return true.
+func (ctx *Context) Canceled() bool {
+	if ctx.cancel == nil {
+		return false
+	}
+	err := ctx.cancel.ctx.Err()
+	if err == nil {
+		return false
+	}
+
+	ctx.cancel.mu.Lock()
+	reported := ctx.cancel.reported
+	ctx.cancel.reported = true
+	ctx.cancel.mu.Unlock()
+	if !reported {
+		ctx.Error(CanceledError{Cause: err})
+	}
+	return true
+}
+
+// UseKey marks the given key of the object as known if this context is
+// tracking keys, and deletes it from the object if this context consumes
+// keys.
+//
+// Since the key may be deleted, UseKey must be called after the value has been
+// read from the object. A forked context defers the deletion until it is
+// joined.
+func (ctx *Context) UseKey(m map[string]interface{}, key string) {
+	if ctx.Keys != nil {
+		ctx.Keys.Use(m, key)
+	}
+	if ctx.Consume {
+		ctx.consume(m, key)
+	}
+}
+
+// consume deletes the key from the object, or records it to be deleted when
+// this context is joined if it is a fork.
+func (ctx *Context) consume(m map[string]interface{}, key string) {
+	if ctx.collected != nil {
+		ctx.consumed = append(ctx.consumed, usedKey{m: m, key: key})
+		return
+	}
+	delete(m, key)
+}
+
+// MarkKey marks the given key of the object as known if this context is
+// tracking keys.
+//
+// Unlike UseKey, MarkKey never deletes the key, so it is used by code such as
+// validators which must not modify the object.
+func (ctx *Context) MarkKey(m map[string]interface{}, key string) {
+	if ctx.Keys != nil {
+		ctx.Keys.Use(m, key)
+	}
+}
+
 // Reset resets the context error count and last error values.
 func (ctx *Context) Reset() {
 	ctx.lastErr = nil
@@ -1,8 +1,11 @@
 package errctx_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -46,6 +49,249 @@ func TestContext(t *testing.T) {
 			require.Equal(t, expected, ctx)
 		})
 	})
+	t.Run("UseKey", func(t *testing.T) {
+		t.Parallel()
+		t.Run("NoTracker", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New()
+			ctx.UseKey(map[string]interface{}{}, "one")
+			require.Nil(t, ctx.Keys)
+		})
+		t.Run("Tracker", func(t *testing.T) {
+			t.Parallel()
+			m := map[string]interface{}{"one": 1}
+			ctx := errctx.New()
+			ctx.Keys = errctx.NewKeyTracker()
+			ctx.UseKey(m, "one")
+			require.True(t, ctx.Keys.Used(m, "one"))
+		})
+		t.Run("Consume", func(t *testing.T) {
+			t.Parallel()
+			m := map[string]interface{}{"one": 1, "two": 2}
+			ctx := errctx.New()
+			ctx.Consume = true
+			ctx.UseKey(m, "one")
+			require.Equal(t, map[string]interface{}{"two": 2}, m)
+		})
+		t.Run("Mark", func(t *testing.T) {
+			t.Parallel()
+			m := map[string]interface{}{"one": 1}
+			ctx := errctx.New()
+			ctx.Keys = errctx.NewKeyTracker()
+			ctx.Consume = true
+			ctx.MarkKey(m, "one")
+			require.True(t, ctx.Keys.Used(m, "one"))
+			require.Equal(t, map[string]interface{}{"one": 1}, m)
+			errctx.New().MarkKey(m, "one")
+		})
+	})
+	t.Run("EnterLeave", func(t *testing.T) {
+		t.Parallel()
+		t.Run("Balanced", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New()
+			s := ctx.Enter(mpath.Key("one"), mpath.Index(2))
+			require.Equal(t, "one[2]", ctx.Path.String())
+			ctx.Leave(s)
+			require.Len(t, ctx.Path.Elements, 0)
+		})
+		t.Run("Unbalanced", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New()
+			ctx.Path.Add(mpath.Key("root"))
+			s := ctx.Enter(mpath.Key("one"))
+			ctx.Path.Add(mpath.Key("two"))
+			ctx.Leave(s)
+			require.Equal(t, "root", ctx.Path.String())
+		})
+		t.Run("Nested", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New()
+			outer := ctx.Enter(mpath.Key("one"))
+			inner := ctx.Enter(mpath.Key("two"))
+			require.Equal(t, "one.two", ctx.Path.String())
+			ctx.Leave(inner)
+			require.Equal(t, "one", ctx.Path.String())
+			ctx.Leave(outer)
+			require.Len(t, ctx.Path.Elements, 0)
+		})
+	})
+	t.Run("WithKey", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.WithKey("one", func() {
+			ctx.Error(errors.New("test"))
+			ctx.Path.Add(mpath.Key("dangling"))
+		})
+		require.Equal(t, "one: test\n", sb.String())
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("WithIndex", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Path.Add(mpath.Key("one"))
+		ctx.WithIndex(3, func() {
+			ctx.Error(errors.New("test"))
+		})
+		require.Equal(t, "one[3]: test\n", sb.String())
+		require.Len(t, ctx.Path.Elements, 1)
+	})
+	t.Run("WithPanic", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New()
+		require.Panics(t, func() {
+			ctx.WithKey("one", func() { panic("test") })
+		})
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("Fork", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Keys = errctx.NewKeyTracker()
+		ctx.Lenient = true
+		ctx.Consume = true
+		ctx.Path.Add(mpath.Key("root"))
+		child := ctx.Fork()
+		require.Equal(t, ctx.Path, child.Path)
+		require.Same(t, ctx.Keys, child.Keys)
+		require.True(t, child.Lenient)
+		require.True(t, child.Consume)
+		child.Path.Add(mpath.Key("child"))
+		child.Error(errors.New("test"))
+		require.Len(t, ctx.Path.Elements, 1)
+		require.Zero(t, ctx.ErrorCount())
+		require.Zero(t, sb.String())
+	})
+	t.Run("Join", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Path.Add(mpath.Key("root"))
+		c1 := ctx.Fork()
+		c2 := ctx.Fork()
+		c2.ErrorWithKey(errors.New("two"), "b")
+		c1.ErrorWithKey(errors.New("one"), "a")
+		c1.ErrorWithIndex(errors.New("three"), 1)
+		ctx.Join(c1, c2)
+		require.Equal(t, 3, ctx.ErrorCount())
+		require.EqualError(t, ctx.LastError(), "two")
+		require.Equal(t, "root.a: one\nroot[1]: three\nroot.b: two\n", sb.String())
+	})
+	t.Run("JoinNotForked", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New()
+		other := errctx.New()
+		other.Error(errors.New("lost"))
+		require.Panics(t, func() { ctx.Join(other) })
+	})
+	t.Run("Parallel", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		fns := make([]func(*errctx.Context), 0, 8)
+		expected := &strings.Builder{}
+		for i := 0; i < 8; i++ {
+			i := i
+			fns = append(fns, func(ctx *errctx.Context) {
+				ctx.WithIndex(i, func() {
+					ctx.Error(errors.New("test"))
+				})
+			})
+			fmt.Fprintf(expected, "[%d]: test\n", i)
+		}
+		ctx.Parallel(fns...)
+		require.Equal(t, 8, ctx.ErrorCount())
+		require.Equal(t, expected.String(), sb.String())
+	})
+	t.Run("ParallelConsume", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New()
+		ctx.Consume = true
+		m := map[string]interface{}{}
+		fns := make([]func(*errctx.Context), 0, 8)
+		for i := 0; i < 8; i++ {
+			key := strconv.Itoa(i)
+			m[key] = i
+			fns = append(fns, func(ctx *errctx.Context) {
+				for j := 0; j < 100; j++ {
+					_ = m[key]
+					ctx.UseKey(m, key)
+				}
+				// The keys are shared, so none are deleted before the join.
+				if len(m) != 9 {
+					ctx.Error(errors.New("key deleted before join"))
+				}
+			})
+		}
+		m["unused"] = true
+		ctx.Parallel(fns...)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, map[string]interface{}{"unused": true}, m)
+	})
+	t.Run("JoinNestedConsume", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New()
+		ctx.Consume = true
+		m := map[string]interface{}{"a": 1, "b": 2}
+		child := ctx.Fork()
+		grandchild := child.Fork()
+		grandchild.UseKey(m, "a")
+		child.Join(grandchild)
+		require.Contains(t, m, "a")
+		ctx.Join(child)
+		require.Equal(t, map[string]interface{}{"b": 2}, m)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+		t.Run("NoContext", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New()
+			require.Equal(t, context.Background(), ctx.Context())
+			require.False(t, ctx.Canceled())
+			require.Zero(t, ctx.ErrorCount())
+		})
+		t.Run("Active", func(t *testing.T) {
+			t.Parallel()
+			c, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx := errctx.New()
+			ctx.SetContext(c)
+			require.Equal(t, c, ctx.Context())
+			require.False(t, ctx.Canceled())
+			require.Zero(t, ctx.ErrorCount())
+		})
+		t.Run("Done", func(t *testing.T) {
+			t.Parallel()
+			c, cancel := context.WithCancel(context.Background())
+			cancel()
+			sb := &strings.Builder{}
+			ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+			ctx.SetContext(c)
+			ctx.Path.Add(mpath.Key("one"))
+			require.True(t, ctx.Canceled())
+			require.True(t, ctx.Canceled())
+			require.Equal(t, 1, ctx.ErrorCount())
+			require.True(t, errors.Is(ctx.LastError(), errctx.ErrCanceled))
+			require.True(t, errors.Is(ctx.LastError(), context.Canceled))
+			require.Equal(t, "one: canceled: context canceled\n", sb.String())
+		})
+		t.Run("Fork", func(t *testing.T) {
+			t.Parallel()
+			c, cancel := context.WithCancel(context.Background())
+			ctx := errctx.New()
+			ctx.SetContext(c)
+			c1, c2 := ctx.Fork(), ctx.Fork()
+			cancel()
+			require.True(t, c1.Canceled())
+			require.True(t, c2.Canceled())
+			require.True(t, ctx.Canceled())
+			ctx.Join(c1, c2)
+			require.Equal(t, 1, ctx.ErrorCount())
+		})
+	})
 	t.Run("ErrorCount", func(t *testing.T) {
 		t.Parallel()
 		ctx := errctx.New()
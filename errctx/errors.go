@@ -0,0 +1,33 @@
+package errctx
+
+import "github.com/tvarney/maputil/consterr"
+
+// ErrCanceled is the root error of a canceled validation.
+const ErrCanceled consterr.Error = "canceled"
+
+// CanceledError is an error indicating that validation stopped because the
+// context.Context attached to a Context was done.
+type CanceledError struct {
+	Cause error
+}
+
+// Error returns the string representation of this canceled error.
+func (e CanceledError) Error() string {
+	if e.Cause == nil {
+		return string(ErrCanceled)
+	}
+	return string(ErrCanceled) + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the parent error for this canceled error.
+func (e CanceledError) Unwrap() error {
+	return ErrCanceled
+}
+
+// Is returns true if the target is the cause of this canceled error.
+//
+// This allows checking for context.Canceled or context.DeadlineExceeded with
+// errors.Is.
+func (e CanceledError) Is(target error) bool {
+	return e.Cause != nil && target == e.Cause
+}
@@ -0,0 +1,37 @@
+package errctx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+)
+
+func TestCanceledError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		t.Run("NoCause", func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, string(errctx.ErrCanceled), errctx.CanceledError{}.Error())
+		})
+		t.Run("Cause", func(t *testing.T) {
+			t.Parallel()
+			e := errctx.CanceledError{Cause: context.DeadlineExceeded}
+			require.Equal(t, string(errctx.ErrCanceled)+": context deadline exceeded", e.Error())
+		})
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(errctx.CanceledError{}, errctx.ErrCanceled))
+	})
+	t.Run("Is", func(t *testing.T) {
+		t.Parallel()
+		e := errctx.CanceledError{Cause: context.Canceled}
+		require.True(t, errors.Is(e, context.Canceled))
+		require.False(t, errors.Is(e, context.DeadlineExceeded))
+		require.False(t, errors.Is(errctx.CanceledError{}, context.Canceled))
+	})
+}
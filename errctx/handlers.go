@@ -3,6 +3,7 @@ package errctx
 import (
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/tvarney/maputil/mpath"
 )
@@ -47,3 +48,49 @@ func (m *MultiHandler) Add(p *mpath.Path, err error) {
 		h.Add(p, err)
 	}
 }
+
+// Entry is an error and the path at which it occurred.
+type Entry struct {
+	Path *mpath.Path
+	Err  error
+}
+
+// ErrorCollector is an ErrorHandler which stores errors in the order they
+// were added.
+//
+// An ErrorCollector is safe for concurrent use; the Entries field should only
+// be read once all errors have been added.
+type ErrorCollector struct {
+	mu      sync.Mutex
+	Entries []Entry
+}
+
+// Add stores the given error along with a copy of the path.
+func (h *ErrorCollector) Add(p *mpath.Path, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.Entries = append(h.Entries, Entry{Path: p.Copy(), Err: err})
+}
+
+// SyncHandler is an ErrorHandler which serializes calls to a child handler,
+// allowing handlers which are not safe for concurrent use to be shared.
+type SyncHandler struct {
+	mu      sync.Mutex
+	Handler ErrorHandler
+}
+
+// NewSyncHandler returns a new SyncHandler wrapping the given handler.
+func NewSyncHandler(h ErrorHandler) *SyncHandler {
+	return &SyncHandler{
+		Handler: h,
+	}
+}
+
+// Add dispatches the given error to the child handler while holding a lock.
+func (h *SyncHandler) Add(p *mpath.Path, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.Handler.Add(p, err)
+}
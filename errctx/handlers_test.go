@@ -3,6 +3,7 @@ package errctx_test
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -44,3 +45,40 @@ func TestMultiHandler(t *testing.T) {
 		require.Equal(t, expected, sb2.String())
 	})
 }
+
+func TestErrorCollector(t *testing.T) {
+	t.Parallel()
+	t.Run("Add", func(t *testing.T) {
+		t.Parallel()
+		ec := &errctx.ErrorCollector{}
+		p := mpath.New(mpath.DotNotation{}, mpath.Key("one"))
+		err1 := errors.New("error one")
+		ec.Add(p, err1)
+		p.Add(mpath.Key("two"))
+		err2 := errors.New("error two")
+		ec.Add(p, err2)
+		require.Equal(t, []errctx.Entry{
+			{Path: mpath.New(mpath.DotNotation{}, mpath.Key("one")), Err: err1},
+			{Path: mpath.New(mpath.DotNotation{}, mpath.Key("one"), mpath.Key("two")), Err: err2},
+		}, ec.Entries)
+	})
+}
+
+func TestSyncHandler(t *testing.T) {
+	t.Parallel()
+	t.Run("Add", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		h := errctx.NewSyncHandler(&errctx.ErrorPrinter{Stream: sb})
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				h.Add(mpath.New(mpath.DotNotation{}, mpath.Key("a")), errors.New("b"))
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, strings.Repeat("a: b\n", 10), sb.String())
+	})
+}
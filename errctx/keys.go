@@ -0,0 +1,98 @@
+package errctx
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// KeyTracker records which keys of an object have been read.
+//
+// Objects are identified by the address of the underlying map, so a tracker
+// should only be used while the maps it has seen are still referenced.
+//
+// A KeyTracker is safe for concurrent use, allowing it to be shared by forked
+// contexts.
+type KeyTracker struct {
+	mu      sync.Mutex
+	objects map[uintptr]map[string]struct{}
+}
+
+// NewKeyTracker returns a new, empty KeyTracker.
+func NewKeyTracker() *KeyTracker {
+	return &KeyTracker{
+		objects: map[uintptr]map[string]struct{}{},
+	}
+}
+
+func objectID(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+// Use marks the given key of the object as known.
+//
+// A key may be marked as known even if the object does not contain it; this
+// allows optional keys to be offered as suggestions for unknown keys.
+func (t *KeyTracker) Use(m map[string]interface{}, key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := objectID(m)
+	keys, ok := t.objects[id]
+	if !ok {
+		keys = map[string]struct{}{}
+		t.objects[id] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+// Used returns true if the given key of the object has been marked as known.
+func (t *KeyTracker) Used(m map[string]interface{}, key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.objects[objectID(m)][key]
+	return ok
+}
+
+// Known returns the sorted list of keys marked as known for the object.
+func (t *KeyTracker) Known(m map[string]interface{}) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := t.objects[objectID(m)]
+	if len(keys) == 0 {
+		return nil
+	}
+	known := make([]string, 0, len(keys))
+	for k := range keys {
+		known = append(known, k)
+	}
+	sort.Strings(known)
+	return known
+}
+
+// Unused returns the sorted list of keys in the object which have not been
+// marked as known.
+func (t *KeyTracker) Unused(m map[string]interface{}) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := t.objects[objectID(m)]
+	var unused []string
+	for k := range m {
+		if _, ok := keys[k]; !ok {
+			unused = append(unused, k)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// Forget removes all records for the given object.
+func (t *KeyTracker) Forget(m map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.objects, objectID(m))
+}
@@ -0,0 +1,49 @@
+package errctx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+)
+
+func TestKeyTracker(t *testing.T) {
+	t.Parallel()
+	t.Run("Use", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"a": 1, "b": 2}
+		kt := errctx.NewKeyTracker()
+		require.False(t, kt.Used(m, "a"))
+		kt.Use(m, "a")
+		require.True(t, kt.Used(m, "a"))
+		require.False(t, kt.Used(m, "b"))
+	})
+	t.Run("Known", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"a": 1}
+		kt := errctx.NewKeyTracker()
+		require.Nil(t, kt.Known(m))
+		kt.Use(m, "c")
+		kt.Use(m, "a")
+		require.Equal(t, []string{"a", "c"}, kt.Known(m))
+	})
+	t.Run("Unused", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"a": 1, "b": 2, "c": 3}
+		other := map[string]interface{}{"a": 1}
+		kt := errctx.NewKeyTracker()
+		require.Equal(t, []string{"a", "b", "c"}, kt.Unused(m))
+		kt.Use(m, "b")
+		kt.Use(other, "c")
+		require.Equal(t, []string{"a", "c"}, kt.Unused(m))
+		require.Equal(t, []string{"a"}, kt.Unused(other))
+	})
+	t.Run("Forget", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"a": 1}
+		kt := errctx.NewKeyTracker()
+		kt.Use(m, "a")
+		kt.Forget(m)
+		require.False(t, kt.Used(m, "a"))
+	})
+}
@@ -16,6 +16,9 @@ const (
 
 	// ErrMissingRequiredValue is the root error of a missing required value.
 	ErrMissingRequiredValue consterr.Error = "missing required value"
+
+	// ErrUnknownKey is the root error of an unexpected key in an object.
+	ErrUnknownKey consterr.Error = "unknown key"
 )
 
 // InvalidTypeError is an error indicating that a type did not match the
@@ -105,3 +108,198 @@ func (e MissingRequiredValueError) Error() string {
 func (e MissingRequiredValueError) Unwrap() error {
 	return ErrMissingRequiredValue
 }
+
+// UnknownKeyError is an error indicating that an object contained a key which
+// was not expected.
+//
+// If Suggestion is not empty, it holds the name of a known key which is close
+// to the unknown key and is included in the error message.
+type UnknownKeyError struct {
+	Key        string
+	Suggestion string
+}
+
+// Error returns the string representation of this unknown key error.
+func (e UnknownKeyError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("%s %q", string(ErrUnknownKey), e.Key)
+	}
+	return fmt.Sprintf("%s %q; did you mean %q?", string(ErrUnknownKey), e.Key, e.Suggestion)
+}
+
+// Unwrap returns the parent error for this unknown key error.
+func (e UnknownKeyError) Unwrap() error {
+	return ErrUnknownKey
+}
+
+// OutOfRangeError is an error indicating that a value was outside of an
+// allowed range.
+//
+// Constraint is the name of the bound which was violated, such as "minimum"
+// or "maxLength", and Limit is the string representation of the bound.
+type OutOfRangeError struct {
+	Value      string
+	Constraint string
+	Limit      string
+}
+
+// Error returns the string representation of this out of range error.
+func (e OutOfRangeError) Error() string {
+	return fmt.Sprintf("%s %s; %s is %s", string(ErrInvalidValue), e.Value, e.Constraint, e.Limit)
+}
+
+// Unwrap returns the parent error for this out of range error.
+func (e OutOfRangeError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// PatternError is an error indicating that a string did not match a required
+// regular expression.
+type PatternError struct {
+	Value   string
+	Pattern string
+}
+
+// Error returns the string representation of this pattern error.
+func (e PatternError) Error() string {
+	return fmt.Sprintf("%s %q; expected to match %q", string(ErrInvalidValue), e.Value, e.Pattern)
+}
+
+// Unwrap returns the parent error for this pattern error.
+func (e PatternError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// OverflowError is an error indicating that an integer value does not fit in
+// the requested integer type.
+//
+// Type is the name of the Go type, such as "int64" or "uint16".
+type OverflowError struct {
+	Value string
+	Type  string
+}
+
+// Error returns the string representation of this overflow error.
+func (e OverflowError) Error() string {
+	return fmt.Sprintf("%s %s; overflows %s", string(ErrInvalidValue), e.Value, e.Type)
+}
+
+// Unwrap returns the parent error for this overflow error.
+func (e OverflowError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// InvalidValueError is an error indicating that a value had the right type
+// but was not in the expected format.
+//
+// Value is the string representation of the value, and Expected describes the
+// accepted format, such as `duration such as "1m30s"`. Reason, if not empty,
+// explains why the value was rejected.
+type InvalidValueError struct {
+	Value    string
+	Expected string
+	Reason   string
+}
+
+// Error returns the string representation of this invalid value error.
+func (e InvalidValueError) Error() string {
+	msg := string(ErrInvalidValue) + " " + e.Value
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	if e.Expected != "" {
+		msg += "; expected " + e.Expected
+	}
+	return msg
+}
+
+// Unwrap returns the parent error for this invalid value error.
+func (e InvalidValueError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// ParseError is an error indicating that a string could not be parsed as the
+// type it was leniently converted to.
+//
+// Type is the name of the target type, such as TypeInteger.
+type ParseError struct {
+	Value string
+	Type  string
+}
+
+// Error returns the string representation of this parse error.
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s %q; cannot parse as %s", string(ErrInvalidValue), e.Value, e.Type)
+}
+
+// Unwrap returns the parent error for this parse error.
+func (e ParseError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// FormatError is an error indicating that a string could not be parsed in a
+// format such as a URL, a network address or a regular expression.
+//
+// Value, Expected and Reason are as for InvalidValueError, and the message is
+// the same. Err is the error returned by the parser, if any, so that it may be
+// reached with errors.As; the error is still matched by ErrInvalidValue.
+type FormatError struct {
+	Value    string
+	Expected string
+	Reason   string
+	Err      error
+}
+
+// Error returns the string representation of this format error.
+func (e FormatError) Error() string {
+	return InvalidValueError{Value: e.Value, Expected: e.Expected, Reason: e.Reason}.Error()
+}
+
+// Unwrap returns the error of the parser, or the parent error for this format
+// error if there is none.
+func (e FormatError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	return ErrInvalidValue
+}
+
+// Is returns true if the target is ErrInvalidValue, which is the parent error
+// for this format error even when Unwrap returns the error of the parser.
+func (e FormatError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// KeyError is an error indicating that the value of a key within an object
+// could not be converted.
+type KeyError struct {
+	Key string
+	Err error
+}
+
+// Error returns the string representation of this key error.
+func (e KeyError) Error() string {
+	return fmt.Sprintf("key %q: %s", e.Key, e.Err.Error())
+}
+
+// Unwrap returns the error of the value.
+func (e KeyError) Unwrap() error {
+	return e.Err
+}
+
+// PathError is an error indicating that a path could not be used to look up a
+// value.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the string representation of this path error.
+func (e PathError) Error() string {
+	return fmt.Sprintf("invalid path %q: %s", e.Path, e.Err.Error())
+}
+
+// Unwrap returns the reason the path could not be used.
+func (e PathError) Unwrap() error {
+	return e.Err
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/mpath"
 )
 
 func TestConstError(t *testing.T) {
@@ -150,3 +151,140 @@ func TestMissingRequiredValueError(t *testing.T) {
 		require.True(t, errors.Is(maputil.MissingRequiredValueError{}, maputil.ErrMissingRequiredValue))
 	})
 }
+
+func TestUnknownKeyError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		t.Run("NoSuggestion", func(t *testing.T) {
+			t.Parallel()
+			e := maputil.UnknownKeyError{Key: "one"}
+			require.Equal(t, string(maputil.ErrUnknownKey)+` "one"`, e.Error())
+		})
+		t.Run("Suggestion", func(t *testing.T) {
+			t.Parallel()
+			e := maputil.UnknownKeyError{Key: "timout", Suggestion: "timeout"}
+			require.Equal(
+				t, string(maputil.ErrUnknownKey)+` "timout"; did you mean "timeout"?`,
+				e.Error(),
+			)
+		})
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.UnknownKeyError{}, maputil.ErrUnknownKey))
+	})
+}
+
+func TestOutOfRangeError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.OutOfRangeError{Value: "0", Constraint: "minimum", Limit: "1"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+" 0; minimum is 1", e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.OutOfRangeError{}, maputil.ErrInvalidValue))
+	})
+}
+
+func TestPatternError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.PatternError{Value: "A", Pattern: "^[a-z]$"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "A"; expected to match "^[a-z]$"`, e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.PatternError{}, maputil.ErrInvalidValue))
+	})
+}
+
+func TestOverflowError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.OverflowError{Value: "300", Type: "uint8"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+" 300; overflows uint8", e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.OverflowError{}, maputil.ErrInvalidValue))
+	})
+}
+
+func TestInvalidValueError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.InvalidValueError{Value: `"soon"`, Expected: "duration"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "soon"; expected duration`, e.Error())
+		e = maputil.InvalidValueError{Value: `"soon"`}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "soon"`, e.Error())
+		e = maputil.InvalidValueError{Value: `"x:y"`, Expected: "host and port", Reason: "invalid port"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "x:y" (invalid port); expected host and port`, e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.InvalidValueError{}, maputil.ErrInvalidValue))
+	})
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.ParseError{Value: "8o8o", Type: maputil.TypeInteger}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "8o8o"; cannot parse as integer`, e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.ParseError{}, maputil.ErrInvalidValue))
+	})
+}
+
+func TestFormatError(t *testing.T) {
+	t.Parallel()
+	inner := errors.New("bad syntax")
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := maputil.FormatError{Value: `"a(b"`, Expected: "regular expression", Reason: "missing )", Err: inner}
+		require.Equal(t, string(maputil.ErrInvalidValue)+` "a(b" (missing )); expected regular expression`, e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(maputil.FormatError{}, maputil.ErrInvalidValue))
+		e := maputil.FormatError{Err: inner}
+		require.True(t, errors.Is(e, maputil.ErrInvalidValue))
+		require.True(t, errors.Is(e, inner))
+	})
+}
+
+func TestKeyError(t *testing.T) {
+	t.Parallel()
+	inner := maputil.InvalidTypeError{Expected: []string{maputil.TypeString}, Actual: maputil.TypeInteger}
+	e := maputil.KeyError{Key: "app", Err: inner}
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, `key "app": invalid type integer; expected string`, e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(e, maputil.ErrInvalidType))
+	})
+}
+
+func TestPathError(t *testing.T) {
+	t.Parallel()
+	e := maputil.PathError{Path: "a[", Err: mpath.ErrUnmatchedOpenBracket}
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, `invalid path "a[": `+mpath.ErrUnmatchedOpenBracket.Error(), e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(e, mpath.ErrUnmatchedOpenBracket))
+	})
+}
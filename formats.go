@@ -0,0 +1,139 @@
+package maputil
+
+import (
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+)
+
+// HostPort is a network address made up of a host and a port.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// String returns the address in the form accepted by net.Dial.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(int(hp.Port)))
+}
+
+// AsBase64Bytes attempts to coerce the value into bytes encoded as a base64
+// string.
+//
+// The standard base64 alphabet is used, and padding is optional.
+func AsBase64Bytes(v interface{}) ([]byte, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		b, err = base64.RawStdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return nil, FormatError{Value: strconv.Quote(s), Expected: "base64 data", Err: err}
+	}
+	return b, nil
+}
+
+// AsCIDR attempts to coerce the value into an IP network given in CIDR
+// notation, such as "10.0.0.0/8" or "fd00::/8".
+//
+// The network address is masked, so "10.1.2.3/8" results in 10.0.0.0/8.
+func AsCIDR(v interface{}) (*net.IPNet, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return nil, err
+	}
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, FormatError{Value: strconv.Quote(s), Expected: `CIDR network such as "10.0.0.0/8"`, Err: err}
+	}
+	return n, nil
+}
+
+// AsHostPort attempts to coerce the value into a host and port, such as
+// "localhost:8080" or "[::1]:443".
+//
+// The host may be empty, but the port must be a number from 0 to 65535.
+func AsHostPort(v interface{}) (HostPort, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return HostPort{}, err
+	}
+	expected := `host and port such as "localhost:8080"`
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		reason := ""
+		var addrErr *net.AddrError
+		if errors.As(err, &addrErr) {
+			reason = addrErr.Err
+		}
+		return HostPort{}, FormatError{Value: strconv.Quote(s), Expected: expected, Reason: reason, Err: err}
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, FormatError{Value: strconv.Quote(s), Expected: expected, Reason: "invalid port", Err: err}
+	}
+	return HostPort{Host: host, Port: uint16(p)}, nil
+}
+
+// AsIP attempts to coerce the value into an IPv4 or IPv6 address.
+func AsIP(v interface{}) (net.IP, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, FormatError{Value: strconv.Quote(s), Expected: "IP address"}
+	}
+	return ip, nil
+}
+
+// AsRegexp attempts to coerce the value into a compiled regular expression.
+//
+// The expression uses the syntax of the regexp package.
+func AsRegexp(v interface{}) (*regexp.Regexp, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		reason := ""
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			reason = string(syntaxErr.Code)
+		}
+		return nil, FormatError{Value: strconv.Quote(s), Expected: "regular expression", Reason: reason, Err: err}
+	}
+	return re, nil
+}
+
+// AsURL attempts to coerce the value into an absolute URL, such as
+// "https://example.com/path".
+func AsURL(v interface{}) (*url.URL, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return nil, err
+	}
+	expected := "absolute URL"
+	u, err := url.Parse(s)
+	if err != nil {
+		reason := ""
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			reason = urlErr.Err.Error()
+		}
+		return nil, FormatError{Value: strconv.Quote(s), Expected: expected, Reason: reason, Err: err}
+	}
+	if !u.IsAbs() {
+		return nil, FormatError{Value: strconv.Quote(s), Expected: expected, Reason: "missing scheme"}
+	}
+	return u, nil
+}
@@ -0,0 +1,150 @@
+package maputil_test
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp/syntax"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsBase64Bytes(t *testing.T) {
+	t.Parallel()
+	b, err := maputil.AsBase64Bytes("aGVsbG8=")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), b)
+	b, err = maputil.AsBase64Bytes("aGVsbG8")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), b)
+
+	_, err = maputil.AsBase64Bytes("not base64!")
+	require.EqualError(t, err, `invalid value "not base64!"; expected base64 data`)
+	_, err = maputil.AsBase64Bytes(testInteger)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+}
+
+func TestAsCIDR(t *testing.T) {
+	t.Parallel()
+	n, err := maputil.AsCIDR("10.1.2.3/8")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/8", n.String())
+	n, err = maputil.AsCIDR("fd00::/8")
+	require.NoError(t, err)
+	require.Equal(t, "fd00::/8", n.String())
+
+	_, err = maputil.AsCIDR("10.0.0.0")
+	require.EqualError(t, err, `invalid value "10.0.0.0"; expected CIDR network such as "10.0.0.0/8"`)
+	var parseErr *net.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "10.0.0.0", parseErr.Text)
+}
+
+func TestAsHostPort(t *testing.T) {
+	t.Parallel()
+	tests := map[string]maputil.HostPort{
+		"localhost:8080": {Host: "localhost", Port: 8080},
+		"[::1]:443":      {Host: "::1", Port: 443},
+		":0":             {Host: "", Port: 0},
+	}
+	for s, expected := range tests {
+		hp, err := maputil.AsHostPort(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, hp, s)
+		require.Equal(t, s, hp.String())
+	}
+
+	_, err := maputil.AsHostPort("localhost")
+	require.EqualError(t, err, `invalid value "localhost" (missing port in address); expected host and port such as "localhost:8080"`)
+	_, err = maputil.AsHostPort("localhost:http")
+	require.EqualError(t, err, `invalid value "localhost:http" (invalid port); expected host and port such as "localhost:8080"`)
+	var numErr *strconv.NumError
+	require.True(t, errors.As(err, &numErr))
+	_, err = maputil.AsHostPort("localhost:70000")
+	require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+	require.True(t, errors.Is(err, strconv.ErrRange))
+}
+
+func TestAsIP(t *testing.T) {
+	t.Parallel()
+	ip, err := maputil.AsIP("192.168.0.1")
+	require.NoError(t, err)
+	require.True(t, ip.Equal(net.IPv4(192, 168, 0, 1)))
+	ip, err = maputil.AsIP("::1")
+	require.NoError(t, err)
+	require.True(t, ip.Equal(net.IPv6loopback))
+
+	_, err = maputil.AsIP("256.0.0.1")
+	require.EqualError(t, err, `invalid value "256.0.0.1"; expected IP address`)
+}
+
+func TestAsRegexp(t *testing.T) {
+	t.Parallel()
+	re, err := maputil.AsRegexp("^[a-z]+$")
+	require.NoError(t, err)
+	require.True(t, re.MatchString("abc"))
+
+	_, err = maputil.AsRegexp("a(b")
+	require.EqualError(t, err, `invalid value "a(b" (missing closing )); expected regular expression`)
+	require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+	var syntaxErr *syntax.Error
+	require.True(t, errors.As(err, &syntaxErr))
+	require.Equal(t, syntax.ErrMissingParen, syntaxErr.Code)
+}
+
+func TestAsURL(t *testing.T) {
+	t.Parallel()
+	u, err := maputil.AsURL("https://example.com:8443/path?q=1")
+	require.NoError(t, err)
+	require.Equal(t, "https", u.Scheme)
+	require.Equal(t, "example.com:8443", u.Host)
+	require.Equal(t, "/path", u.Path)
+
+	_, err = maputil.AsURL("/relative/path")
+	require.EqualError(t, err, `invalid value "/relative/path" (missing scheme); expected absolute URL`)
+	_, err = maputil.AsURL("http://[::1")
+	require.EqualError(t, err, `invalid value "http://[::1" (missing ']' in host); expected absolute URL`)
+	var urlErr *url.Error
+	require.True(t, errors.As(err, &urlErr))
+	require.Equal(t, "parse", urlErr.Op)
+	var formatErr maputil.FormatError
+	require.True(t, errors.As(err, &formatErr))
+	require.Equal(t, "absolute URL", formatErr.Expected)
+}
+
+func TestFormatAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{keyGood: "127.0.0.1:80", keyBad: testInteger, keyBadVal: testString}
+
+	hp, err := maputil.RequireHostPort(m, keyGood)
+	require.NoError(t, err)
+	require.Equal(t, uint16(80), hp.Port)
+	_, err = maputil.RequireURL(m, keyMissing)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+	_, err = maputil.RequireIP(m, keyBad)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+
+	dv := net.IPv4zero
+	ip, err := maputil.OptionalIP(m, keyBadVal, dv)
+	require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+	require.Equal(t, dv, ip)
+	re, err := maputil.OptionalRegexp(m, keyMissing, nil)
+	require.NoError(t, err)
+	require.Nil(t, re)
+
+	n, ok, err := maputil.GetCIDR(m, keyMissing)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, n)
+
+	c := maputil.Copy(m)
+	c[keyBadVal] = "dmFsdWU="
+	b, ok, err := maputil.PopBase64Bytes(c, keyBadVal)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte(testString), b)
+	require.NotContains(t, c, keyBadVal)
+}
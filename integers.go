@@ -0,0 +1,160 @@
+package maputil
+
+import (
+	"math"
+	"strconv"
+)
+
+// AsInt8 attempts to coerce the value into an 8-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int8.
+func AsInt8(v interface{}) (int8, error) {
+	i, err := asSigned(v, 8, "int8")
+	return int8(i), err
+}
+
+// AsInt16 attempts to coerce the value into a 16-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int16.
+func AsInt16(v interface{}) (int16, error) {
+	i, err := asSigned(v, 16, "int16")
+	return int16(i), err
+}
+
+// AsInt32 attempts to coerce the value into a 32-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int32.
+func AsInt32(v interface{}) (int32, error) {
+	i, err := asSigned(v, 32, "int32")
+	return int32(i), err
+}
+
+// AsUint8 attempts to coerce the value into an 8-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint8.
+func AsUint8(v interface{}) (uint8, error) {
+	u, err := asUnsigned(v, 8, "uint8")
+	return uint8(u), err
+}
+
+// AsUint16 attempts to coerce the value into a 16-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint16.
+func AsUint16(v interface{}) (uint16, error) {
+	u, err := asUnsigned(v, 16, "uint16")
+	return uint16(u), err
+}
+
+// AsUint32 attempts to coerce the value into a 32-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint32.
+func AsUint32(v interface{}) (uint32, error) {
+	u, err := asUnsigned(v, 32, "uint32")
+	return uint32(u), err
+}
+
+// AsUint64 attempts to coerce the value into a 64-bit unsigned integer.
+//
+// Unlike AsInteger, AsUint64 accepts values up to math.MaxUint64. An
+// OverflowError is returned if the value is negative or too large.
+func AsUint64(v interface{}) (uint64, error) {
+	switch d := v.(type) {
+	case int:
+		return intToUint(int64(d))
+	case int8:
+		return intToUint(int64(d))
+	case int16:
+		return intToUint(int64(d))
+	case int32:
+		return intToUint(int64(d))
+	case int64:
+		return intToUint(d)
+	case uint:
+		return uint64(d), nil
+	case uint8:
+		return uint64(d), nil
+	case uint16:
+		return uint64(d), nil
+	case uint32:
+		return uint64(d), nil
+	case uint64:
+		return d, nil
+	case float32:
+		return truncFloatUint(float64(d))
+	case float64:
+		return truncFloatUint(d)
+	case GenericNumber:
+		if i, err := d.Int64(); err == nil {
+			return intToUint(i)
+		}
+		if u, err := strconv.ParseUint(d.String(), 10, 64); err == nil {
+			return u, nil
+		}
+		f, _ := d.Float64()
+		u, err := truncFloatUint(f)
+		if _, ok := err.(OverflowError); ok {
+			return 0, OverflowError{Value: d.String(), Type: "uint64"}
+		}
+		return u, err
+	}
+	return 0, InvalidTypeError{
+		Expected: []string{TypeInteger},
+		Actual:   TypeName(v),
+	}
+}
+
+func asSigned(v interface{}, bits uint, name string) (int64, error) {
+	i, err := AsInteger(v)
+	if err != nil {
+		if o, ok := err.(OverflowError); ok {
+			o.Type = name
+			return 0, o
+		}
+		return 0, err
+	}
+	if i < -1<<(bits-1) || i > 1<<(bits-1)-1 {
+		return 0, OverflowError{Value: strconv.FormatInt(i, 10), Type: name}
+	}
+	return i, nil
+}
+
+func asUnsigned(v interface{}, bits uint, name string) (uint64, error) {
+	u, err := AsUint64(v)
+	if err != nil {
+		if o, ok := err.(OverflowError); ok {
+			o.Type = name
+			return 0, o
+		}
+		return 0, err
+	}
+	if u > 1<<bits-1 {
+		return 0, OverflowError{Value: strconv.FormatUint(u, 10), Type: name}
+	}
+	return u, nil
+}
+
+func intToUint(i int64) (uint64, error) {
+	if i < 0 {
+		return 0, OverflowError{Value: strconv.FormatInt(i, 10), Type: "uint64"}
+	}
+	return uint64(i), nil
+}
+
+func truncFloatUint(v float64) (uint64, error) {
+	if math.Trunc(v) != v {
+		return 0, InvalidTypeError{
+			Expected: []string{TypeInteger},
+			Actual:   TypeNumber,
+		}
+	}
+	if v < 0 || v >= 1<<64 {
+		return 0, OverflowError{Value: strconv.FormatFloat(v, 'g', -1, 64), Type: "uint64"}
+	}
+	return uint64(v), nil
+}
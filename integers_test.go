@@ -0,0 +1,129 @@
+package maputil_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsSizedIntegers(t *testing.T) {
+	t.Parallel()
+	type conv func(v interface{}) (interface{}, error)
+	wrap := func(name string) conv {
+		switch name {
+		case "int8":
+			return func(v interface{}) (interface{}, error) { return maputil.AsInt8(v) }
+		case "int16":
+			return func(v interface{}) (interface{}, error) { return maputil.AsInt16(v) }
+		case "int32":
+			return func(v interface{}) (interface{}, error) { return maputil.AsInt32(v) }
+		case "uint8":
+			return func(v interface{}) (interface{}, error) { return maputil.AsUint8(v) }
+		case "uint16":
+			return func(v interface{}) (interface{}, error) { return maputil.AsUint16(v) }
+		case "uint32":
+			return func(v interface{}) (interface{}, error) { return maputil.AsUint32(v) }
+		}
+		return func(v interface{}) (interface{}, error) { return maputil.AsUint64(v) }
+	}
+
+	tests := []struct {
+		typ      string
+		data     interface{}
+		expected interface{}
+		overflow string
+	}{
+		{"int8", 127, int8(127), ""},
+		{"int8", -128.0, int8(-128), ""},
+		{"int8", 128, int8(0), "128"},
+		{"int8", int64(-129), int8(0), "-129"},
+		{"int16", 32767, int16(32767), ""},
+		{"int16", 32768, int16(0), "32768"},
+		{"int32", json.Number("-2147483648"), int32(math.MinInt32), ""},
+		{"int32", uint64(math.MaxUint64), int32(0), "18446744073709551615"},
+		{"uint8", uint8(255), uint8(255), ""},
+		{"uint8", 256, uint8(0), "256"},
+		{"uint8", -1, uint8(0), "-1"},
+		{"uint16", 65535.0, uint16(65535), ""},
+		{"uint16", 70000, uint16(0), "70000"},
+		{"uint32", int64(math.MaxUint32), uint32(math.MaxUint32), ""},
+		{"uint32", int64(math.MaxUint32) + 1, uint32(0), "4294967296"},
+		{"uint64", uint64(math.MaxUint64), uint64(math.MaxUint64), ""},
+		{"uint64", json.Number("18446744073709551615"), uint64(math.MaxUint64), ""},
+		{"uint64", json.Number("18446744073709551616"), uint64(0), "18446744073709551616"},
+		{"uint64", float32(1), uint64(1), ""},
+		{"uint64", 1e20, uint64(0), "1e+20"},
+		{"uint64", -1.0, uint64(0), "-1"},
+		{"uint64", int8(-1), uint64(0), "-1"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.typ, func(t *testing.T) {
+			t.Parallel()
+			v, err := wrap(tc.typ)(tc.data)
+			require.Equal(t, tc.expected, v)
+			if tc.overflow == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, maputil.OverflowError{Value: tc.overflow, Type: tc.typ}.Error())
+		})
+	}
+
+	t.Run("InvalidType", func(t *testing.T) {
+		t.Parallel()
+		for _, typ := range []string{"int8", "uint16", "uint64"} {
+			_, err := wrap(typ)("1")
+			require.EqualError(t, err, maputil.InvalidTypeError{
+				Actual:   maputil.TypeString,
+				Expected: []string{maputil.TypeInteger},
+			}.Error())
+			_, err = wrap(typ)(1.5)
+			require.EqualError(t, err, maputil.InvalidTypeError{
+				Actual:   maputil.TypeNumber,
+				Expected: []string{maputil.TypeInteger},
+			}.Error())
+		}
+	})
+}
+
+func TestSizedAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{keyGood: 80, keyBad: testString, keyBadVal: 70000}
+
+	u, err := maputil.RequireUint16(m, keyGood)
+	require.NoError(t, err)
+	require.Equal(t, uint16(80), u)
+	_, err = maputil.RequireUint16(m, keyMissing)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+	_, err = maputil.RequireUint16(m, keyBadVal)
+	require.EqualError(t, err, "invalid value 70000; overflows uint16")
+
+	u, err = maputil.OptionalUint16(m, keyBadVal, 443)
+	require.Error(t, err)
+	require.Equal(t, uint16(443), u)
+	u, err = maputil.OptionalUint16(m, keyMissing, 443)
+	require.NoError(t, err)
+	require.Equal(t, uint16(443), u)
+
+	i, ok, err := maputil.GetInt32(m, keyBadVal)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int32(70000), i)
+	_, ok, err = maputil.GetInt8(m, keyBad)
+	require.True(t, ok)
+	require.Error(t, err)
+
+	d := maputil.Copy(m)
+	u64, ok, err := maputil.PopUint64(d, keyGood)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(80), u64)
+	require.NotContains(t, d, keyGood)
+	_, ok, err = maputil.PopUint64(d, keyGood)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,180 @@
+// Package tags parses the `map` struct tags shared by the reflection based
+// encoding and decoding functions.
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Name is the struct tag key used by this package.
+const Name = "map"
+
+// Field describes a single struct field which maps to a key of an object.
+type Field struct {
+	// Name is the key of the object the field maps to.
+	Name string
+
+	// Index is the index sequence of the field for reflect.Value.FieldByIndex.
+	Index []int
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Required marks the field as required when decoding.
+	Required bool
+
+	// OmitEmpty marks the field to be omitted when encoding a zero value.
+	OmitEmpty bool
+
+	// Enum, if not empty, is the list of allowed string values.
+	Enum []string
+
+	// Default, if HasDefault is true, is the value used when decoding an
+	// object which does not contain the key. It is one of the JSON-like types
+	// understood by the maputil package.
+	Default    interface{}
+	HasDefault bool
+}
+
+var cache sync.Map
+
+// Fields returns the fields of the given struct type.
+//
+// Unexported fields and fields tagged with "-" are skipped. Embedded structs
+// without a tag name have their fields promoted into the parent. The result
+// is cached per type.
+//
+// Fields panics if a tag is malformed, as this is a programming error.
+func Fields(t reflect.Type) []Field {
+	if f, ok := cache.Load(t); ok {
+		return f.([]Field)
+	}
+	fields := collect(t, nil)
+	f, _ := cache.LoadOrStore(t, fields)
+	return f.([]Field)
+}
+
+func collect(t reflect.Type, index []int) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(Name)
+		if tag == "-" {
+			continue
+		}
+
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		name, opts, err := Parse(tag)
+		if err != nil {
+			panic(fmt.Sprintf("maputil: invalid %s tag on %s.%s: %v", Name, t, sf.Name, err))
+		}
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collect(sf.Type, idx)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		f := Field{
+			Name:      name,
+			Index:     idx,
+			Type:      sf.Type,
+			Required:  opts.Required,
+			OmitEmpty: opts.OmitEmpty,
+			Enum:      opts.Enum,
+		}
+		if opts.HasDefault {
+			dv, err := parseDefault(sf.Type, opts.Default)
+			if err != nil {
+				panic(fmt.Sprintf("maputil: invalid %s tag on %s.%s: %v", Name, t, sf.Name, err))
+			}
+			f.Default = dv
+			f.HasDefault = true
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// Options holds the options of a parsed tag.
+type Options struct {
+	Required   bool
+	OmitEmpty  bool
+	Enum       []string
+	Default    string
+	HasDefault bool
+}
+
+// Parse splits a `map` tag into the key name and its options.
+//
+// The name is empty if the tag does not give one. Only the syntax of the
+// options is validated; default values are not checked against any type.
+func Parse(tag string) (string, Options, error) {
+	parts := strings.Split(tag, ",")
+	opts := Options{}
+	for _, opt := range parts[1:] {
+		key, value := opt, ""
+		hasValue := false
+		if i := strings.IndexByte(opt, '='); i >= 0 {
+			key, value, hasValue = opt[:i], opt[i+1:], true
+		}
+
+		switch key {
+		case "required":
+			opts.Required = true
+		case "omitempty":
+			opts.OmitEmpty = true
+		case "enum":
+			if !hasValue || value == "" {
+				return "", opts, fmt.Errorf("option %q requires a value", key)
+			}
+			opts.Enum = strings.Split(value, "|")
+		case "default":
+			if !hasValue {
+				return "", opts, fmt.Errorf("option %q requires a value", key)
+			}
+			opts.Default = value
+			opts.HasDefault = true
+		case "":
+		default:
+			return "", opts, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	if opts.Required && opts.HasDefault {
+		return "", opts, fmt.Errorf("field may not be both required and have a default")
+	}
+	return parts[0], opts, nil
+}
+
+// parseDefault converts the string form of a default value to the JSON-like
+// value which would represent it in an object.
+func parseDefault(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 0, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 0, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, t.Bits())
+	case reflect.String, reflect.Interface:
+		return value, nil
+	}
+	return nil, fmt.Errorf("default values are not supported for %s", t)
+}
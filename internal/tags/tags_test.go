@@ -0,0 +1,121 @@
+package tags_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/internal/tags"
+)
+
+type embedded struct {
+	Inner string `map:"inner"`
+}
+
+type tagged struct {
+	embedded
+	Port    int    `map:"port,required"`
+	Mode    string `map:"mode,enum=a|b|c"`
+	Name    string `map:"name,default=foo"`
+	Count   *uint8 `map:"count,default=0x10,omitempty"`
+	Skipped string `map:"-"`
+	Plain   bool
+	Ratio   float64 `map:",default=0.5"`
+	private int
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+	t.Run("Tagged", func(t *testing.T) {
+		t.Parallel()
+		fields := tags.Fields(reflect.TypeOf(tagged{}))
+		names := make([]string, 0, len(fields))
+		for _, f := range fields {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{"inner", "port", "mode", "name", "count", "Plain", "Ratio"}, names)
+		require.Equal(t, []int{0, 0}, fields[0].Index)
+		require.True(t, fields[1].Required)
+		require.Equal(t, []string{"a", "b", "c"}, fields[2].Enum)
+		require.True(t, fields[3].HasDefault)
+		require.Equal(t, "foo", fields[3].Default)
+		require.True(t, fields[4].OmitEmpty)
+		require.Equal(t, uint64(16), fields[4].Default)
+		require.False(t, fields[5].HasDefault)
+		require.Equal(t, 0.5, fields[6].Default)
+	})
+	t.Run("Cached", func(t *testing.T) {
+		t.Parallel()
+		f1 := tags.Fields(reflect.TypeOf(tagged{}))
+		f2 := tags.Fields(reflect.TypeOf(tagged{}))
+		require.Equal(t, reflect.ValueOf(f1).Pointer(), reflect.ValueOf(f2).Pointer())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		t.Run("UnknownOption", func(t *testing.T) {
+			t.Parallel()
+			type bad struct {
+				A int `map:"a,bogus"`
+			}
+			require.Panics(t, func() { tags.Fields(reflect.TypeOf(bad{})) })
+		})
+		t.Run("BadDefault", func(t *testing.T) {
+			t.Parallel()
+			type bad struct {
+				A int `map:"a,default=ten"`
+			}
+			require.Panics(t, func() { tags.Fields(reflect.TypeOf(bad{})) })
+		})
+		t.Run("UnsupportedDefault", func(t *testing.T) {
+			t.Parallel()
+			type bad struct {
+				A []int `map:"a,default=1"`
+			}
+			require.Panics(t, func() { tags.Fields(reflect.TypeOf(bad{})) })
+		})
+		t.Run("EmptyEnum", func(t *testing.T) {
+			t.Parallel()
+			type bad struct {
+				A string `map:"a,enum="`
+			}
+			require.Panics(t, func() { tags.Fields(reflect.TypeOf(bad{})) })
+		})
+		t.Run("RequiredDefault", func(t *testing.T) {
+			t.Parallel()
+			type bad struct {
+				A string `map:"a,required,default=x"`
+			}
+			require.Panics(t, func() { tags.Fields(reflect.TypeOf(bad{})) })
+		})
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		name, opts, err := tags.Parse("")
+		require.NoError(t, err)
+		require.Empty(t, name)
+		require.Equal(t, tags.Options{}, opts)
+	})
+	t.Run("Options", func(t *testing.T) {
+		t.Parallel()
+		name, opts, err := tags.Parse("mode,omitempty,enum=a|b,default=a")
+		require.NoError(t, err)
+		require.Equal(t, "mode", name)
+		require.Equal(t, tags.Options{
+			OmitEmpty:  true,
+			Enum:       []string{"a", "b"},
+			Default:    "a",
+			HasDefault: true,
+		}, opts)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := tags.Parse("a,default")
+		require.EqualError(t, err, `option "default" requires a value`)
+		_, _, err = tags.Parse("a,bogus")
+		require.EqualError(t, err, `unknown option "bogus"`)
+	})
+}
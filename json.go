@@ -0,0 +1,37 @@
+package maputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// DecodeJSON reads a single JSON value from the reader in lossless number
+// mode.
+//
+// Numbers are kept as json.Number rather than being converted to float64, so
+// integers of any size and decimals of any precision are preserved exactly.
+// All of the accessors of this package accept json.Number values, and
+// AsBigInt and AsBigFloat convert them without loss.
+func DecodeJSON(r io.Reader) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// UnmarshalJSON parses the JSON data in lossless number mode.
+//
+// See DecodeJSON for details. Unlike DecodeJSON, an error is returned if the
+// data holds anything after the first JSON value.
+func UnmarshalJSON(data []byte) (interface{}, error) {
+	if !json.Valid(data) {
+		// Unmarshal reports why the data is invalid.
+		var v interface{}
+		return nil, json.Unmarshal(data, &v)
+	}
+	return DecodeJSON(bytes.NewReader(data))
+}
@@ -0,0 +1,42 @@
+package maputil_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+	v, err := maputil.DecodeJSON(strings.NewReader(`{"id": 123456789012345678901234567890, "ratio": 0.1}`))
+	require.NoError(t, err)
+	m, err := maputil.AsObject(v)
+	require.NoError(t, err)
+	require.Equal(t, json.Number("123456789012345678901234567890"), m["id"])
+	require.Equal(t, maputil.TypeInteger, maputil.TypeName(m["id"]))
+	require.Equal(t, maputil.TypeNumber, maputil.TypeName(m["ratio"]))
+
+	i, err := maputil.RequireBigInt(m, "id")
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890", i.String())
+	_, err = maputil.RequireInteger(m, "id")
+	require.EqualError(t, err, "invalid value 123456789012345678901234567890; overflows int64")
+
+	_, err = maputil.DecodeJSON(strings.NewReader(`{`))
+	require.Error(t, err)
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	v, err := maputil.UnmarshalJSON([]byte(`[1, 2.5, "a"]`))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{json.Number("1"), json.Number("2.5"), "a"}, v)
+
+	_, err = maputil.UnmarshalJSON([]byte(`1 2`))
+	require.Error(t, err)
+	_, err = maputil.UnmarshalJSON([]byte(``))
+	require.Error(t, err)
+}
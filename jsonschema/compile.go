@@ -0,0 +1,454 @@
+// Package jsonschema validates JSON-like values against JSON Schema documents.
+//
+// Schema documents are given as the JSON-like values produced by decoding a
+// JSON or YAML file, and are compiled once into a Schema which may be used to
+// validate any number of instances. Every violation is reported to an
+// errctx.Context at the path of the offending instance value, wrapped in a
+// ValidationError which records the location of the failing keyword.
+//
+// The following keywords of draft 2020-12 are supported:
+//
+//	$ref (within the same document), $defs, type, enum, const, required,
+//	properties, additionalProperties, items, minimum, maximum,
+//	exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems,
+//	maxItems, pattern, allOf, anyOf, oneOf, not
+//
+// Other keywords are ignored. Patterns are compiled with the regexp package
+// and so use RE2 syntax rather than ECMA-262.
+package jsonschema
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tvarney/maputil"
+)
+
+// Draft is the URI of the JSON Schema dialect implemented by this package,
+// used as the "$schema" keyword of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	location string
+	always   *bool
+
+	ref     *Schema
+	refPath string
+
+	types    []string
+	enum     []interface{}
+	hasEnum  bool
+	constant interface{}
+	hasConst bool
+
+	required   []string
+	properties map[string]*Schema
+	propNames  []string
+	additional *Schema
+	items      *Schema
+
+	minimum   *float64
+	maximum   *float64
+	exclMin   *float64
+	exclMax   *float64
+	minLength *int
+	maxLength *int
+	minItems  *int
+	maxItems  *int
+	pattern   *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+type compiler struct {
+	root    interface{}
+	schemas map[string]*Schema
+	refs    []*Schema
+}
+
+// Compile compiles a JSON Schema document.
+//
+// The document must be an object or a boolean. An error wrapping
+// ErrInvalidSchema is returned if any supported keyword has an invalid value,
+// a $ref can not be resolved, or a $ref refers back to itself without
+// descending into a property or item of the instance.
+func Compile(doc interface{}) (*Schema, error) {
+	c := &compiler{
+		root:    doc,
+		schemas: map[string]*Schema{},
+	}
+	s, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// References are resolved after the document has been compiled so that
+	// they may refer to any part of it, including recursively.
+	for i := 0; i < len(c.refs); i++ {
+		r := c.refs[i]
+		target, err := c.resolve(r.refPath, r.location+"/$ref")
+		if err != nil {
+			return nil, err
+		}
+		r.ref = target
+	}
+	if err := c.checkCycles(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkCycles returns an error if a $ref can reach itself through keywords
+// which apply to the same instance, since validating against it would recurse
+// forever.
+func (c *compiler) checkCycles() error {
+	for _, r := range c.refs {
+		if reaches(r.ref, r, map[*Schema]bool{}) {
+			return c.invalid(r.location+"/$ref", "reference "+strconv.Quote(r.refPath)+" is cyclic")
+		}
+	}
+	return nil
+}
+
+// reaches returns true if the target schema is applied to the same instance
+// as the given schema, either directly or through its subschemas.
+func reaches(s, target *Schema, seen map[*Schema]bool) bool {
+	if s == target {
+		return true
+	}
+	if seen[s] {
+		return false
+	}
+	seen[s] = true
+
+	subs := make([]*Schema, 0, 1+len(s.allOf)+len(s.anyOf)+len(s.oneOf)+1)
+	subs = append(subs, s.ref)
+	subs = append(subs, s.allOf...)
+	subs = append(subs, s.anyOf...)
+	subs = append(subs, s.oneOf...)
+	subs = append(subs, s.not)
+	for _, sub := range subs {
+		if sub != nil && reaches(sub, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compiler) compile(v interface{}, loc string) (*Schema, error) {
+	if s, ok := c.schemas[loc]; ok {
+		return s, nil
+	}
+
+	s := &Schema{location: loc}
+	c.schemas[loc] = s
+	if b, ok := v.(bool); ok {
+		s.always = &b
+		return s, nil
+	}
+	m, err := maputil.AsObject(v)
+	if err != nil {
+		return nil, c.invalid(loc, "schema must be an object or boolean")
+	}
+
+	if ref, ok := m["$ref"]; ok {
+		str, err := maputil.AsString(ref)
+		if err != nil {
+			return nil, c.invalid(loc+"/$ref", "must be a string")
+		}
+		s.refPath = str
+		c.refs = append(c.refs, s)
+	}
+	if defs, ok := m["$defs"]; ok {
+		o, err := maputil.AsObject(defs)
+		if err != nil {
+			return nil, c.invalid(loc+"/$defs", "must be an object")
+		}
+		for _, k := range sortedKeys(o) {
+			if _, err := c.compile(o[k], loc+"/$defs/"+escape(k)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	steps := []func(*Schema, map[string]interface{}, string) error{
+		c.compileType,
+		c.compileValues,
+		c.compileObject,
+		c.compileArray,
+		c.compileBounds,
+		c.compileString,
+		c.compileComposition,
+	}
+	for _, step := range steps {
+		if err := step(s, m, loc); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (c *compiler) compileType(s *Schema, m map[string]interface{}, loc string) error {
+	v, ok := m["type"]
+	if !ok {
+		return nil
+	}
+	valid := map[string]bool{
+		maputil.TypeArray: true, maputil.TypeBoolean: true, maputil.TypeInteger: true,
+		maputil.TypeNull: true, maputil.TypeNumber: true, maputil.TypeObject: true,
+		maputil.TypeString: true,
+	}
+	switch d := v.(type) {
+	case string:
+		s.types = []string{d}
+	case []interface{}:
+		for _, e := range d {
+			str, err := maputil.AsString(e)
+			if err != nil {
+				return c.invalid(loc+"/type", "must be a string or array of strings")
+			}
+			s.types = append(s.types, str)
+		}
+	default:
+		return c.invalid(loc+"/type", "must be a string or array of strings")
+	}
+	for _, t := range s.types {
+		if !valid[t] {
+			return c.invalid(loc+"/type", "unknown type "+strconv.Quote(t))
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileValues(s *Schema, m map[string]interface{}, loc string) error {
+	if v, ok := m["enum"]; ok {
+		a, err := maputil.AsArray(v)
+		if err != nil {
+			return c.invalid(loc+"/enum", "must be an array")
+		}
+		s.enum, s.hasEnum = a, true
+	}
+	if v, ok := m["const"]; ok {
+		s.constant, s.hasConst = v, true
+	}
+	return nil
+}
+
+func (c *compiler) compileObject(s *Schema, m map[string]interface{}, loc string) error {
+	if v, ok := m["required"]; ok {
+		a, err := maputil.AsArray(v)
+		if err != nil {
+			return c.invalid(loc+"/required", "must be an array of strings")
+		}
+		for _, e := range a {
+			str, err := maputil.AsString(e)
+			if err != nil {
+				return c.invalid(loc+"/required", "must be an array of strings")
+			}
+			s.required = append(s.required, str)
+		}
+	}
+	if v, ok := m["properties"]; ok {
+		o, err := maputil.AsObject(v)
+		if err != nil {
+			return c.invalid(loc+"/properties", "must be an object")
+		}
+		s.properties = make(map[string]*Schema, len(o))
+		s.propNames = sortedKeys(o)
+		for _, k := range s.propNames {
+			ps, err := c.compile(o[k], loc+"/properties/"+escape(k))
+			if err != nil {
+				return err
+			}
+			s.properties[k] = ps
+		}
+	}
+	if v, ok := m["additionalProperties"]; ok {
+		as, err := c.compile(v, loc+"/additionalProperties")
+		if err != nil {
+			return err
+		}
+		s.additional = as
+	}
+	return nil
+}
+
+func (c *compiler) compileArray(s *Schema, m map[string]interface{}, loc string) error {
+	if v, ok := m["items"]; ok {
+		is, err := c.compile(v, loc+"/items")
+		if err != nil {
+			return err
+		}
+		s.items = is
+	}
+	var err error
+	if s.minItems, err = c.count(m, "minItems", loc); err != nil {
+		return err
+	}
+	if s.maxItems, err = c.count(m, "maxItems", loc); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *compiler) compileBounds(s *Schema, m map[string]interface{}, loc string) error {
+	bounds := []struct {
+		keyword string
+		target  **float64
+	}{
+		{"minimum", &s.minimum},
+		{"maximum", &s.maximum},
+		{"exclusiveMinimum", &s.exclMin},
+		{"exclusiveMaximum", &s.exclMax},
+	}
+	for _, b := range bounds {
+		v, ok := m[b.keyword]
+		if !ok {
+			continue
+		}
+		f, err := maputil.AsNumber(v)
+		if err != nil {
+			return c.invalid(loc+"/"+b.keyword, "must be a number")
+		}
+		*b.target = &f
+	}
+	return nil
+}
+
+func (c *compiler) compileString(s *Schema, m map[string]interface{}, loc string) error {
+	var err error
+	if s.minLength, err = c.count(m, "minLength", loc); err != nil {
+		return err
+	}
+	if s.maxLength, err = c.count(m, "maxLength", loc); err != nil {
+		return err
+	}
+	if v, ok := m["pattern"]; ok {
+		str, err := maputil.AsString(v)
+		if err != nil {
+			return c.invalid(loc+"/pattern", "must be a string")
+		}
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return c.invalid(loc+"/pattern", err.Error())
+		}
+		s.pattern = re
+	}
+	return nil
+}
+
+func (c *compiler) compileComposition(s *Schema, m map[string]interface{}, loc string) error {
+	lists := []struct {
+		keyword string
+		target  *[]*Schema
+	}{
+		{"allOf", &s.allOf},
+		{"anyOf", &s.anyOf},
+		{"oneOf", &s.oneOf},
+	}
+	for _, l := range lists {
+		v, ok := m[l.keyword]
+		if !ok {
+			continue
+		}
+		a, err := maputil.AsArray(v)
+		if err != nil || len(a) == 0 {
+			return c.invalid(loc+"/"+l.keyword, "must be a non-empty array")
+		}
+		for i, e := range a {
+			sub, err := c.compile(e, loc+"/"+l.keyword+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			*l.target = append(*l.target, sub)
+		}
+	}
+	if v, ok := m["not"]; ok {
+		ns, err := c.compile(v, loc+"/not")
+		if err != nil {
+			return err
+		}
+		s.not = ns
+	}
+	return nil
+}
+
+// count reads a non-negative integer keyword.
+func (c *compiler) count(m map[string]interface{}, keyword, loc string) (*int, error) {
+	v, ok := m[keyword]
+	if !ok {
+		return nil, nil
+	}
+	i, err := maputil.AsInteger(v)
+	if err != nil || i < 0 {
+		return nil, c.invalid(loc+"/"+keyword, "must be a non-negative integer")
+	}
+	n := int(i)
+	return &n, nil
+}
+
+// resolve finds the schema referred to by a $ref within the document.
+func (c *compiler) resolve(ref, loc string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, c.invalid(loc, "only references within the document are supported")
+	}
+	fragment, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, c.invalid(loc, "invalid reference "+strconv.Quote(ref))
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return nil, c.invalid(loc, "invalid reference "+strconv.Quote(ref))
+	}
+	if s, ok := c.schemas[fragment]; ok {
+		return s, nil
+	}
+
+	node := c.root
+	if fragment != "" {
+		for _, tok := range strings.Split(fragment[1:], "/") {
+			tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+			switch d := node.(type) {
+			case map[string]interface{}:
+				v, ok := d[tok]
+				if !ok {
+					return nil, c.invalid(loc, "unresolved reference "+strconv.Quote(ref))
+				}
+				node = v
+			case []interface{}:
+				i, err := strconv.Atoi(tok)
+				if err != nil || i < 0 || i >= len(d) {
+					return nil, c.invalid(loc, "unresolved reference "+strconv.Quote(ref))
+				}
+				node = d[i]
+			default:
+				return nil, c.invalid(loc, "unresolved reference "+strconv.Quote(ref))
+			}
+		}
+	}
+	return c.compile(node, fragment)
+}
+
+func (c *compiler) invalid(loc, reason string) error {
+	return SchemaError{Location: "#" + loc, Reason: reason}
+}
+
+// escape encodes a key as a JSON pointer token.
+func escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/jsonschema"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+	t.Run("Boolean", func(t *testing.T) {
+		t.Parallel()
+		s, err := jsonschema.Compile(true)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+	t.Run("Recursive", func(t *testing.T) {
+		t.Parallel()
+		_, err := jsonschema.Compile(map[string]interface{}{
+			"$defs": map[string]interface{}{
+				"node": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"children": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"$ref": "#/$defs/node"},
+						},
+					},
+				},
+			},
+			"$ref": "#/$defs/node",
+		})
+		require.NoError(t, err)
+	})
+
+	invalid := []struct {
+		name string
+		doc  interface{}
+		msg  string
+	}{
+		{"NotSchema", 1, "invalid schema at #: schema must be an object or boolean"},
+		{"Type", map[string]interface{}{"type": 1}, "invalid schema at #/type: must be a string or array of strings"},
+		{"UnknownType", map[string]interface{}{"type": "int"}, `invalid schema at #/type: unknown type "int"`},
+		{"Enum", map[string]interface{}{"enum": "a"}, "invalid schema at #/enum: must be an array"},
+		{"Required", map[string]interface{}{"required": []interface{}{1}}, "invalid schema at #/required: must be an array of strings"},
+		{"Properties", map[string]interface{}{"properties": []interface{}{}}, "invalid schema at #/properties: must be an object"},
+		{"Property", map[string]interface{}{"properties": map[string]interface{}{"a/b": 1}}, "invalid schema at #/properties/a~1b: schema must be an object or boolean"},
+		{"Minimum", map[string]interface{}{"minimum": "1"}, "invalid schema at #/minimum: must be a number"},
+		{"MinLength", map[string]interface{}{"minLength": -1}, "invalid schema at #/minLength: must be a non-negative integer"},
+		{"Pattern", map[string]interface{}{"pattern": "("}, "invalid schema at #/pattern: error parsing regexp: missing closing ): `(`"},
+		{"AnyOf", map[string]interface{}{"anyOf": []interface{}{}}, "invalid schema at #/anyOf: must be a non-empty array"},
+		{"RefType", map[string]interface{}{"$ref": 1}, "invalid schema at #/$ref: must be a string"},
+		{"RefExternal", map[string]interface{}{"$ref": "other.json"}, "invalid schema at #/$ref: only references within the document are supported"},
+		{"RefMissing", map[string]interface{}{"$ref": "#/$defs/missing"}, `invalid schema at #/$ref: unresolved reference "#/$defs/missing"`},
+		{"RefSelf", map[string]interface{}{"$ref": "#"}, `invalid schema at #/$ref: reference "#" is cyclic`},
+		{"RefCycle", map[string]interface{}{
+			"$defs": map[string]interface{}{"a": map[string]interface{}{"$ref": "#/$defs/a"}},
+			"$ref":  "#/$defs/a",
+		}, `invalid schema at #/$defs/a/$ref: reference "#/$defs/a" is cyclic`},
+		{"RefCycleComposition", map[string]interface{}{
+			"$defs": map[string]interface{}{
+				"a": map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"$ref": "#/$defs/b"}}},
+				"b": map[string]interface{}{"not": map[string]interface{}{"$ref": "#/$defs/a"}},
+			},
+			"$ref": "#/$defs/a",
+		}, `invalid schema at #/$defs/a/anyOf/0/$ref: reference "#/$defs/b" is cyclic`},
+	}
+	for _, tc := range invalid {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := jsonschema.Compile(tc.doc)
+			require.Nil(t, s)
+			require.True(t, errors.Is(err, jsonschema.ErrInvalidSchema))
+			require.EqualError(t, err, tc.msg)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package jsonschema
+
+import (
+	"fmt"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/consterr"
+)
+
+// ErrInvalidSchema is the root error of a schema document which can not be
+// compiled.
+const ErrInvalidSchema consterr.Error = "invalid schema"
+
+// SchemaError is an error indicating that a keyword of a schema document is
+// invalid.
+type SchemaError struct {
+	Location string
+	Reason   string
+}
+
+// Error returns the string representation of this schema error.
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("%s at %s: %s", string(ErrInvalidSchema), e.Location, e.Reason)
+}
+
+// Unwrap returns the parent error for this schema error.
+func (e SchemaError) Unwrap() error {
+	return ErrInvalidSchema
+}
+
+// ValidationError is an error indicating that an instance violated a keyword
+// of a schema.
+//
+// KeywordLocation is a URI fragment holding the JSON pointer to the keyword
+// within the schema document, such as "#/properties/port/maximum". Err holds
+// the underlying error, which unwraps to one of the maputil root errors.
+type ValidationError struct {
+	KeywordLocation string
+	Err             error
+}
+
+// Error returns the string representation of this validation error.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Err.Error(), e.KeywordLocation)
+}
+
+// Unwrap returns the underlying error of this validation error.
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// KeywordError is an error indicating that a value did not satisfy a keyword
+// such as "enum" or "oneOf".
+//
+// Range and length keywords are reported as a maputil.OutOfRangeError and the
+// "pattern" keyword as a maputil.PatternError.
+type KeywordError struct {
+	Value   string
+	Keyword string
+	Limit   string
+}
+
+// Error returns the string representation of this keyword error.
+func (e KeywordError) Error() string {
+	if e.Limit == "" {
+		return fmt.Sprintf("%s %s; %s not satisfied", string(maputil.ErrInvalidValue), e.Value, e.Keyword)
+	}
+	return fmt.Sprintf("%s %s; %s is %s", string(maputil.ErrInvalidValue), e.Value, e.Keyword, e.Limit)
+}
+
+// Unwrap returns the parent error for this keyword error.
+func (e KeywordError) Unwrap() error {
+	return maputil.ErrInvalidValue
+}
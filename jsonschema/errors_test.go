@@ -0,0 +1,49 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/jsonschema"
+)
+
+func TestSchemaError(t *testing.T) {
+	t.Parallel()
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := jsonschema.SchemaError{Location: "#/type", Reason: "must be a string"}
+		require.Equal(t, string(jsonschema.ErrInvalidSchema)+" at #/type: must be a string", e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(jsonschema.SchemaError{}, jsonschema.ErrInvalidSchema))
+	})
+}
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+	inner := maputil.MissingRequiredValueError{Key: "port"}
+	e := jsonschema.ValidationError{KeywordLocation: "#/required", Err: inner}
+	require.Equal(t, inner.Error()+" (#/required)", e.Error())
+	require.True(t, errors.Is(e, maputil.ErrMissingRequiredValue))
+}
+
+func TestKeywordError(t *testing.T) {
+	t.Parallel()
+	t.Run("Limit", func(t *testing.T) {
+		t.Parallel()
+		e := jsonschema.KeywordError{Value: "1", Keyword: "oneOf", Limit: "matched by 2 subschemas"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+" 1; oneOf is matched by 2 subschemas", e.Error())
+	})
+	t.Run("NoLimit", func(t *testing.T) {
+		t.Parallel()
+		e := jsonschema.KeywordError{Value: "1", Keyword: "not"}
+		require.Equal(t, string(maputil.ErrInvalidValue)+" 1; not not satisfied", e.Error())
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, errors.Is(jsonschema.KeywordError{}, maputil.ErrInvalidValue))
+	})
+}
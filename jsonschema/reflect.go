@@ -0,0 +1,204 @@
+package jsonschema
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/internal/tags"
+	"github.com/tvarney/maputil/unpack"
+)
+
+var unpackerType = reflect.TypeOf((*unpack.Unpacker)(nil)).Elem()
+
+// Reflect returns a JSON Schema document describing the values accepted by
+// unpack.Decode for the type of v, which is typically a pointer to a struct.
+//
+// Struct fields are described using their `map` struct tags: required fields
+// are listed in "required", and the enum and default options become the "enum"
+// and "default" keywords. Integer fields are bounded by the range of their Go
+// type, and pointer fields also accept null. Named struct types other than the
+// root are placed in "$defs" and referenced with "$ref", which allows recursive
+// types. Types which implement unpack.Unpacker accept any value, as their
+// format is unknown.
+//
+// Reflect panics if a struct tag is malformed.
+func Reflect(v interface{}) map[string]interface{} {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r := &reflector{
+		root:  t,
+		defs:  map[string]interface{}{},
+		names: map[reflect.Type]string{},
+	}
+	var doc map[string]interface{}
+	if t != nil && t.Kind() == reflect.Struct && !isUnpacker(t) {
+		doc = r.object(t)
+	} else {
+		doc = r.schema(t)
+	}
+	doc["$schema"] = Draft
+	if len(r.defs) > 0 {
+		doc["$defs"] = r.defs
+	}
+	return doc
+}
+
+type reflector struct {
+	root  reflect.Type
+	defs  map[string]interface{}
+	names map[reflect.Type]string
+}
+
+func (r *reflector) schema(t reflect.Type) map[string]interface{} {
+	if t == nil || isUnpacker(t) {
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.schema(t.Elem()))
+	case reflect.Bool:
+		return map[string]interface{}{"type": maputil.TypeBoolean}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": maputil.TypeInteger}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		bits := uint(t.Bits())
+		return map[string]interface{}{
+			"type":    maputil.TypeInteger,
+			"minimum": int64(-1) << (bits - 1),
+			"maximum": int64(1)<<(bits-1) - 1,
+		}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": maputil.TypeInteger, "minimum": int64(0)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{
+			"type":    maputil.TypeInteger,
+			"minimum": int64(0),
+			"maximum": int64(1)<<uint(t.Bits()) - 1,
+		}
+	case reflect.Float32:
+		return map[string]interface{}{
+			"type":    maputil.TypeNumber,
+			"minimum": -math.MaxFloat32,
+			"maximum": math.MaxFloat32,
+		}
+	case reflect.Float64:
+		return map[string]interface{}{"type": maputil.TypeNumber}
+	case reflect.String:
+		return map[string]interface{}{"type": maputil.TypeString}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  maputil.TypeArray,
+			"items": r.schema(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 maputil.TypeObject,
+			"additionalProperties": r.schema(t.Elem()),
+		}
+	case reflect.Struct:
+		return r.ref(t)
+	}
+	// Interfaces accept any value; other kinds can not be decoded at all.
+	return map[string]interface{}{}
+}
+
+// ref returns a reference to the definition of a struct type, adding it to
+// the definitions if needed.
+func (r *reflector) ref(t reflect.Type) map[string]interface{} {
+	if t == r.root {
+		return map[string]interface{}{"$ref": "#"}
+	}
+	if t.Name() == "" {
+		return r.object(t)
+	}
+
+	name, ok := r.names[t]
+	if !ok {
+		// Distinct types with the same name are qualified by their package.
+		name = t.Name()
+		if _, taken := r.defs[name]; taken {
+			name = t.String()
+		}
+		// The name is reserved before describing the type so that recursive
+		// references resolve to it.
+		r.names[t] = name
+		r.defs[name] = nil
+		r.defs[name] = r.object(t)
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + escape(name)}
+}
+
+func (r *reflector) object(t reflect.Type) map[string]interface{} {
+	fields := tags.Fields(t)
+	properties := make(map[string]interface{}, len(fields))
+	var required []interface{}
+	for _, f := range fields {
+		s := r.schema(f.Type)
+		if len(f.Enum) > 0 {
+			enum := make([]interface{}, len(f.Enum))
+			for i, e := range f.Enum {
+				enum[i] = e
+			}
+			// Enums on slices apply to each element, as with unpack.Decode.
+			target := s
+			if items, ok := s["items"].(map[string]interface{}); ok {
+				target = items
+			}
+			// Null is not checked against the enum, as with unpack.Decode.
+			if isNullable(target) {
+				enum = append(enum, nil)
+			}
+			target["enum"] = enum
+		}
+		if f.HasDefault {
+			s["default"] = f.Default
+		}
+		if f.Required {
+			required = append(required, f.Name)
+		}
+		properties[f.Name] = s
+	}
+
+	doc := map[string]interface{}{
+		"type":       maputil.TypeObject,
+		"properties": properties,
+	}
+	if required != nil {
+		doc["required"] = required
+	}
+	return doc
+}
+
+// nullable returns the schema extended to accept null, which unpack.Decode
+// accepts for pointers.
+func nullable(s map[string]interface{}) map[string]interface{} {
+	if isNullable(s) {
+		return s
+	}
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []interface{}{t, maputil.TypeNull}
+		return s
+	}
+	if len(s) == 0 {
+		return s
+	}
+	return map[string]interface{}{
+		"anyOf": []interface{}{s, map[string]interface{}{"type": maputil.TypeNull}},
+	}
+}
+
+// isNullable returns true if the schema describes a type extended to accept
+// null by nullable.
+func isNullable(s map[string]interface{}) bool {
+	types, ok := s["type"].([]interface{})
+	return ok && len(types) == 2 && types[1] == maputil.TypeNull
+}
+
+func isUnpacker(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(unpackerType)
+}
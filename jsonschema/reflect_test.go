@@ -0,0 +1,134 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/jsonschema"
+)
+
+type reflectTLS struct {
+	Cert string `map:"cert,required"`
+}
+
+type reflectNode struct {
+	Name     string        `map:"name"`
+	Children []reflectNode `map:"children"`
+}
+
+type reflectAny struct{}
+
+func (a *reflectAny) UnpackMap(ctx *errctx.Context, v interface{}) {}
+
+type reflectConfig struct {
+	Port    uint16            `map:"port,required"`
+	Mode    string            `map:"mode,enum=a|b,default=a"`
+	Tags    []string          `map:"tags,enum=x|y"`
+	Ratio   float64           `map:"ratio,default=0.5"`
+	TLS     *reflectTLS       `map:"tls"`
+	Nodes   []reflectNode     `map:"nodes"`
+	Labels  map[string]string `map:"labels"`
+	Extra   interface{}       `map:"extra"`
+	Custom  reflectAny        `map:"custom"`
+	Level   int8              `map:"level"`
+	Retries *int              `map:"retries"`
+	Role    *string           `map:"role,enum=r|w"`
+	Ignored string            `map:"-"`
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+	doc := jsonschema.Reflect(&reflectConfig{})
+	require.Equal(t, map[string]interface{}{
+		"$schema":  jsonschema.Draft,
+		"type":     "object",
+		"required": []interface{}{"port"},
+		"properties": map[string]interface{}{
+			"port":  map[string]interface{}{"type": "integer", "minimum": int64(0), "maximum": int64(65535)},
+			"mode":  map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}, "default": "a"},
+			"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": []interface{}{"x", "y"}}},
+			"ratio": map[string]interface{}{"type": "number", "default": 0.5},
+			"tls": map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"$ref": "#/$defs/reflectTLS"},
+				map[string]interface{}{"type": "null"},
+			}},
+			"nodes": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/reflectNode"}},
+			"labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"extra":   map[string]interface{}{},
+			"custom":  map[string]interface{}{},
+			"level":   map[string]interface{}{"type": "integer", "minimum": int64(-128), "maximum": int64(127)},
+			"retries": map[string]interface{}{"type": []interface{}{"integer", "null"}},
+			"role": map[string]interface{}{
+				"type": []interface{}{"string", "null"},
+				"enum": []interface{}{"r", "w", nil},
+			},
+		},
+		"$defs": map[string]interface{}{
+			"reflectTLS": map[string]interface{}{
+				"type":       "object",
+				"required":   []interface{}{"cert"},
+				"properties": map[string]interface{}{"cert": map[string]interface{}{"type": "string"}},
+			},
+			"reflectNode": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":     map[string]interface{}{"type": "string"},
+					"children": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/reflectNode"}},
+				},
+			},
+		},
+	}, doc)
+
+	t.Run("Compile", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, []string{
+			`mode: invalid value "c"; enum not satisfied (#/properties/mode/enum)`,
+			`nodes[0].children[0].name: invalid type integer; expected string (#/$defs/reflectNode/properties/name/type)`,
+			`port: invalid value 70000; maximum is 65535 (#/properties/port/maximum)`,
+			`tls: invalid value object; anyOf not satisfied (#/properties/tls/anyOf)`,
+		}, validate(t, doc, map[string]interface{}{
+			"port": 70000,
+			"mode": "c",
+			"tls":  map[string]interface{}{},
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"children": []interface{}{map[string]interface{}{"name": 1}},
+				},
+			},
+		}))
+	})
+	t.Run("Null", func(t *testing.T) {
+		t.Parallel()
+		require.Empty(t, validate(t, doc, map[string]interface{}{
+			"port":    1,
+			"tls":     nil,
+			"retries": nil,
+			"role":    nil,
+		}))
+		require.Equal(t, []string{
+			`mode: invalid type null; expected string (#/properties/mode/type)`,
+			`mode: invalid value null; enum not satisfied (#/properties/mode/enum)`,
+			`role: invalid value "x"; enum not satisfied (#/properties/role/enum)`,
+		}, validate(t, doc, map[string]interface{}{"port": 1, "mode": nil, "role": "x"}))
+	})
+	t.Run("Recursive", func(t *testing.T) {
+		t.Parallel()
+		doc := jsonschema.Reflect(reflectNode{})
+		require.Equal(t, map[string]interface{}{
+			"$ref": "#",
+		}, doc["properties"].(map[string]interface{})["children"].(map[string]interface{})["items"])
+		require.NotContains(t, doc, "$defs")
+	})
+	t.Run("NonStruct", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, map[string]interface{}{
+			"$schema": jsonschema.Draft,
+			"type":    "array",
+			"items":   map[string]interface{}{"type": "boolean"},
+		}, jsonschema.Reflect([]bool{}))
+	})
+}
@@ -0,0 +1,316 @@
+package jsonschema
+
+import (
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+	"github.com/tvarney/maputil/unpack"
+)
+
+// Validate checks the given value against the schema, reporting every
+// violation to the context at the path of the offending value.
+//
+// Each error is a ValidationError holding the location of the failing keyword
+// within the schema document. Type mismatches wrap a maputil.InvalidTypeError,
+// missing required properties a maputil.MissingRequiredValueError, disallowed
+// additional properties a maputil.UnknownKeyError, range and length failures a
+// maputil.OutOfRangeError, pattern failures a maputil.PatternError, and all
+// other failures a KeywordError.
+func (s *Schema) Validate(ctx *errctx.Context, v interface{}) {
+	if s.always != nil {
+		if !*s.always {
+			s.error(ctx, "", KeywordError{Value: describe(v), Keyword: "false schema"})
+		}
+		return
+	}
+	if s.ref != nil {
+		s.ref.Validate(ctx, v)
+	}
+
+	if len(s.types) > 0 {
+		if err := maputil.Is(v, s.types...); err != nil {
+			s.error(ctx, "type", err)
+		}
+	}
+	if s.hasEnum && !s.inEnum(v) {
+		s.error(ctx, "enum", KeywordError{Value: describe(v), Keyword: "enum"})
+	}
+	if s.hasConst && !equal(v, s.constant) {
+		s.error(ctx, "const", KeywordError{Value: describe(v), Keyword: "const", Limit: describe(s.constant)})
+	}
+
+	switch d := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(ctx, d)
+	case []interface{}:
+		s.validateArray(ctx, d)
+	case string:
+		s.validateString(ctx, d)
+	default:
+		if f, err := maputil.AsNumber(v); err == nil {
+			s.validateNumber(ctx, f)
+		}
+	}
+
+	s.validateComposition(ctx, v)
+}
+
+func (s *Schema) validateObject(ctx *errctx.Context, m map[string]interface{}) {
+	for _, k := range s.required {
+		if _, ok := m[k]; !ok {
+			ctx.ErrorWithKey(ValidationError{
+				KeywordLocation: s.keyword("required"),
+				Err:             maputil.MissingRequiredValueError{Key: k},
+			}, k)
+		}
+	}
+
+	for _, k := range s.propNames {
+		v, ok := m[k]
+		ctx.MarkKey(m, k)
+		if !ok {
+			continue
+		}
+		if ctx.Canceled() {
+			return
+		}
+		scope := ctx.Enter(mpath.Key(k))
+		s.properties[k].Validate(ctx, v)
+		ctx.Leave(scope)
+	}
+
+	if s.additional == nil {
+		return
+	}
+	var extra []string
+	for k := range m {
+		if _, ok := s.properties[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+	for _, k := range extra {
+		if ctx.Canceled() {
+			return
+		}
+		if s.additional.always != nil && !*s.additional.always {
+			ctx.ErrorWithKey(ValidationError{
+				KeywordLocation: s.keyword("additionalProperties"),
+				Err: maputil.UnknownKeyError{
+					Key:        k,
+					Suggestion: unpack.SuggestKey(k, s.propNames),
+				},
+			}, k)
+			continue
+		}
+		v := m[k]
+		ctx.MarkKey(m, k)
+		scope := ctx.Enter(mpath.Key(k))
+		s.additional.Validate(ctx, v)
+		ctx.Leave(scope)
+	}
+}
+
+func (s *Schema) validateArray(ctx *errctx.Context, a []interface{}) {
+	if s.minItems != nil && len(a) < *s.minItems {
+		s.error(ctx, "minItems", lengthError(len(a), "minItems", *s.minItems))
+	}
+	if s.maxItems != nil && len(a) > *s.maxItems {
+		s.error(ctx, "maxItems", lengthError(len(a), "maxItems", *s.maxItems))
+	}
+	if s.items == nil {
+		return
+	}
+	for i, e := range a {
+		if ctx.Canceled() {
+			return
+		}
+		scope := ctx.Enter(mpath.Index(i))
+		s.items.Validate(ctx, e)
+		ctx.Leave(scope)
+	}
+}
+
+func (s *Schema) validateString(ctx *errctx.Context, str string) {
+	n := utf8.RuneCountInString(str)
+	if s.minLength != nil && n < *s.minLength {
+		s.error(ctx, "minLength", lengthError(n, "minLength", *s.minLength))
+	}
+	if s.maxLength != nil && n > *s.maxLength {
+		s.error(ctx, "maxLength", lengthError(n, "maxLength", *s.maxLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		s.error(ctx, "pattern", maputil.PatternError{Value: str, Pattern: s.pattern.String()})
+	}
+}
+
+func (s *Schema) validateNumber(ctx *errctx.Context, f float64) {
+	bounds := []struct {
+		keyword string
+		limit   *float64
+		failed  func(f, limit float64) bool
+	}{
+		{"minimum", s.minimum, func(f, limit float64) bool { return f < limit }},
+		{"exclusiveMinimum", s.exclMin, func(f, limit float64) bool { return f <= limit }},
+		{"maximum", s.maximum, func(f, limit float64) bool { return f > limit }},
+		{"exclusiveMaximum", s.exclMax, func(f, limit float64) bool { return f >= limit }},
+	}
+	for _, b := range bounds {
+		if b.limit != nil && b.failed(f, *b.limit) {
+			s.error(ctx, b.keyword, maputil.OutOfRangeError{
+				Value:      formatFloat(f),
+				Constraint: b.keyword,
+				Limit:      formatFloat(*b.limit),
+			})
+		}
+	}
+}
+
+func (s *Schema) validateComposition(ctx *errctx.Context, v interface{}) {
+	// allOf reports the errors of each subschema directly, while the other
+	// keywords only report that they were not satisfied.
+	for _, sub := range s.allOf {
+		sub.Validate(ctx, v)
+	}
+
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.matches(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			s.error(ctx, "anyOf", KeywordError{Value: describe(v), Keyword: "anyOf"})
+		}
+	}
+
+	if len(s.oneOf) > 0 {
+		count := 0
+		for _, sub := range s.oneOf {
+			if sub.matches(v) {
+				count++
+			}
+		}
+		if count != 1 {
+			s.error(ctx, "oneOf", KeywordError{
+				Value:   describe(v),
+				Keyword: "oneOf",
+				Limit:   "matched by " + strconv.Itoa(count) + " subschemas",
+			})
+		}
+	}
+
+	if s.not != nil && s.not.matches(v) {
+		s.error(ctx, "not", KeywordError{Value: describe(v), Keyword: "not"})
+	}
+}
+
+// matches returns true if the value is valid against the schema.
+func (s *Schema) matches(v interface{}) bool {
+	scratch := errctx.New()
+	s.Validate(scratch, v)
+	return scratch.ErrorCount() == 0
+}
+
+func (s *Schema) inEnum(v interface{}) bool {
+	for _, e := range s.enum {
+		if equal(v, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) keyword(name string) string {
+	if name == "" {
+		return "#" + s.location
+	}
+	return "#" + s.location + "/" + name
+}
+
+func (s *Schema) error(ctx *errctx.Context, keyword string, err error) {
+	ctx.Error(ValidationError{KeywordLocation: s.keyword(keyword), Err: err})
+}
+
+func lengthError(n int, keyword string, limit int) maputil.OutOfRangeError {
+	return maputil.OutOfRangeError{
+		Value:      "of length " + strconv.Itoa(n),
+		Constraint: keyword,
+		Limit:      strconv.Itoa(limit),
+	}
+}
+
+// equal compares two JSON-like values, treating numbers of any type as equal
+// if they have the same value.
+func equal(a, b interface{}) bool {
+	switch da := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		db, ok := b.(bool)
+		return ok && da == db
+	case string:
+		db, ok := b.(string)
+		return ok && da == db
+	case []interface{}:
+		db, ok := b.([]interface{})
+		if !ok || len(da) != len(db) {
+			return false
+		}
+		for i := range da {
+			if !equal(da[i], db[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		db, ok := b.(map[string]interface{})
+		if !ok || len(da) != len(db) {
+			return false
+		}
+		for k, va := range da {
+			vb, ok := db[k]
+			if !ok || !equal(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+
+	fa, err := maputil.AsNumber(a)
+	if err != nil {
+		return false
+	}
+	fb, err := maputil.AsNumber(b)
+	return err == nil && fa == fb
+}
+
+// describe returns a short description of a value for use in error messages.
+func describe(v interface{}) string {
+	switch d := v.(type) {
+	case nil:
+		return maputil.TypeNull
+	case bool:
+		return strconv.FormatBool(d)
+	case string:
+		return strconv.Quote(d)
+	case []interface{}:
+		return "of length " + strconv.Itoa(len(d))
+	case map[string]interface{}:
+		return maputil.TypeObject
+	}
+	if f, err := maputil.AsNumber(v); err == nil {
+		return formatFloat(f)
+	}
+	return maputil.TypeName(v)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,171 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/jsonschema"
+)
+
+func validate(t *testing.T, doc interface{}, v interface{}) []string {
+	t.Helper()
+	s, err := jsonschema.Compile(doc)
+	require.NoError(t, err)
+
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	s.Validate(ctx, v)
+	if sb.Len() == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	doc := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name", "server"},
+		"properties": map[string]interface{}{
+			"name":   map[string]interface{}{"type": "string", "minLength": 1},
+			"server": map[string]interface{}{"$ref": "#/$defs/server"},
+			"servers": map[string]interface{}{
+				"type":     "array",
+				"items":    map[string]interface{}{"$ref": "#/$defs/server"},
+				"maxItems": 2,
+			},
+		},
+		"$defs": map[string]interface{}{
+			"server": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"port"},
+				"properties": map[string]interface{}{
+					"port": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 65535},
+					"mode": map[string]interface{}{"enum": []interface{}{"a", "b"}},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(t, doc, map[string]interface{}{
+			"name":    "n",
+			"server":  map[string]interface{}{"port": 80, "mode": "a"},
+			"servers": []interface{}{map[string]interface{}{"port": 443.0}},
+			"extra":   true,
+		}))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, []string{
+			`name: missing required value "name" (#/required)`,
+			`server.mode: invalid value "c"; enum not satisfied (#/$defs/server/properties/mode/enum)`,
+			`server.port: invalid value 0; minimum is 1 (#/$defs/server/properties/port/minimum)`,
+			`server.pot: unknown key "pot"; did you mean "port"? (#/$defs/server/additionalProperties)`,
+			`servers: invalid value of length 3; maxItems is 2 (#/properties/servers/maxItems)`,
+			`servers[0].port: invalid type number; expected integer (#/$defs/server/properties/port/type)`,
+			`servers[1]: invalid type string; expected object (#/$defs/server/type)`,
+			`servers[2].port: missing required value "port" (#/$defs/server/required)`,
+		}, validate(t, doc, map[string]interface{}{
+			"server": map[string]interface{}{"port": 0, "mode": "c", "pot": 1},
+			"servers": []interface{}{
+				map[string]interface{}{"port": 1.5},
+				"bad",
+				map[string]interface{}{},
+			},
+		}))
+	})
+	t.Run("Consume", func(t *testing.T) {
+		t.Parallel()
+		s, err := jsonschema.Compile(map[string]interface{}{
+			"properties":           map[string]interface{}{"a": map[string]interface{}{"type": "integer"}},
+			"additionalProperties": map[string]interface{}{"type": "boolean"},
+			"allOf": []interface{}{
+				map[string]interface{}{"required": []interface{}{"a", "b"}},
+			},
+		})
+		require.NoError(t, err)
+		m := map[string]interface{}{"a": 1, "b": true}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Consume = true
+		s.Validate(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, map[string]interface{}{"a": 1, "b": true}, m)
+	})
+}
+
+func TestValidateKeywords(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		doc      interface{}
+		value    interface{}
+		expected []string
+	}{
+		{"True", true, 1, nil},
+		{"False", false, 1, []string{`: invalid value 1; false schema not satisfied (#)`}},
+		{"TypeList", map[string]interface{}{"type": []interface{}{"string", "null"}}, nil, nil},
+		{"TypeListInvalid", map[string]interface{}{"type": []interface{}{"string", "null"}}, 1,
+			[]string{`: invalid type integer; expected string or null (#/type)`}},
+		{"NumberAcceptsInteger", map[string]interface{}{"type": "number"}, 1, nil},
+		{"Const", map[string]interface{}{"const": 1}, 1.0, nil},
+		{"ConstInvalid", map[string]interface{}{"const": "a"}, "b",
+			[]string{`: invalid value "b"; const is "a" (#/const)`}},
+		{"EnumObject", map[string]interface{}{"enum": []interface{}{map[string]interface{}{"a": []interface{}{1}}}},
+			map[string]interface{}{"a": []interface{}{int64(1)}}, nil},
+		{"ExclusiveMinimum", map[string]interface{}{"exclusiveMinimum": 1}, 1,
+			[]string{`: invalid value 1; exclusiveMinimum is 1 (#/exclusiveMinimum)`}},
+		{"ExclusiveMaximum", map[string]interface{}{"exclusiveMaximum": 1.5}, 2,
+			[]string{`: invalid value 2; exclusiveMaximum is 1.5 (#/exclusiveMaximum)`}},
+		{"MaxLength", map[string]interface{}{"maxLength": 2}, "äöü",
+			[]string{`: invalid value of length 3; maxLength is 2 (#/maxLength)`}},
+		{"Pattern", map[string]interface{}{"pattern": "^[a-z]+$"}, "A",
+			[]string{`: invalid value "A"; expected to match "^[a-z]+$" (#/pattern)`}},
+		{"MinItems", map[string]interface{}{"minItems": 1}, []interface{}{},
+			[]string{`: invalid value of length 0; minItems is 1 (#/minItems)`}},
+		{"AdditionalSchema", map[string]interface{}{"additionalProperties": map[string]interface{}{"type": "integer"}},
+			map[string]interface{}{"a": 1, "b": "x"},
+			[]string{`b: invalid type string; expected integer (#/additionalProperties/type)`}},
+		{"AllOf", map[string]interface{}{"allOf": []interface{}{
+			map[string]interface{}{"minimum": 1},
+			map[string]interface{}{"maximum": 0},
+		}}, 1, []string{`: invalid value 1; maximum is 0 (#/allOf/1/maximum)`}},
+		{"AnyOf", map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer"},
+		}}, 1, nil},
+		{"AnyOfInvalid", map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer"},
+		}}, true, []string{`: invalid value true; anyOf not satisfied (#/anyOf)`}},
+		{"OneOf", map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "integer"},
+		}}, 1.5, nil},
+		{"OneOfInvalid", map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "integer"},
+		}}, 1, []string{`: invalid value 1; oneOf is matched by 2 subschemas (#/oneOf)`}},
+		{"Not", map[string]interface{}{"not": map[string]interface{}{"type": "null"}}, nil,
+			[]string{`: invalid value null; not not satisfied (#/not)`}},
+		{"RefRoot", map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#"},
+		}, []interface{}{[]interface{}{}, []interface{}{1}},
+			[]string{`[1][0]: invalid type integer; expected array (#/type)`}},
+		{"RefEscaped", map[string]interface{}{
+			"$defs": map[string]interface{}{"a/b c": map[string]interface{}{"type": "string"}},
+			"$ref":  "#/$defs/a~1b%20c",
+		}, 1, []string{`: invalid type integer; expected string (#/$defs/a~1b c/type)`}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, validate(t, tc.doc, tc.value))
+		})
+	}
+}
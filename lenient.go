@@ -0,0 +1,115 @@
+package maputil
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The grammars of integers and numbers match those of JSON.
+var (
+	integerGrammar = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	numberGrammar  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// ParseBoolean parses a string as a boolean.
+//
+// The strings "true", "yes", "on" and "1" are true, and "false", "no", "off"
+// and "0" are false, ignoring case. Surrounding whitespace is not allowed. Any
+// other string results in a ParseError.
+func ParseBoolean(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, ParseError{Value: s, Type: TypeBoolean}
+}
+
+// ParseInteger parses a string as an integer.
+//
+// The string must be an integer as written in JSON: an optional minus sign
+// followed by decimal digits without leading zeros. A ParseError is returned
+// if it is not, and an OverflowError if it does not fit in an int64.
+func ParseInteger(s string) (int64, error) {
+	if !integerGrammar.MatchString(s) {
+		return 0, ParseError{Value: s, Type: TypeInteger}
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, OverflowError{Value: s, Type: "int64"}
+	}
+	return i, nil
+}
+
+// ParseNumber parses a string as a number.
+//
+// The string must be a number as written in JSON, such as "-1", "0.5" or
+// "1e-3"; in particular "NaN", "Inf" and hexadecimal numbers are rejected
+// with a ParseError. An OverflowError is returned if the number is too large
+// to be held in a float64.
+func ParseNumber(s string) (float64, error) {
+	if !numberGrammar.MatchString(s) {
+		return 0, ParseError{Value: s, Type: TypeNumber}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, OverflowError{Value: s, Type: "float64"}
+	}
+	return f, nil
+}
+
+// ParseNull parses a string as null.
+//
+// The empty string and "null" are accepted, as missing values are commonly
+// written as an empty string in environment variables and INI files. Any
+// other string results in a ParseError.
+func ParseNull(s string) error {
+	if s == "" || s == "null" {
+		return nil
+	}
+	return ParseError{Value: s, Type: TypeNull}
+}
+
+// AsBooleanLenient converts the value into a boolean, parsing strings with
+// ParseBoolean.
+//
+// Values which are not strings are converted as with AsBoolean.
+func AsBooleanLenient(v interface{}) (bool, error) {
+	if s, ok := v.(string); ok {
+		return ParseBoolean(s)
+	}
+	return AsBoolean(v)
+}
+
+// AsIntegerLenient converts the value into an integer, parsing strings with
+// ParseInteger.
+//
+// Values which are not strings are converted as with AsInteger.
+func AsIntegerLenient(v interface{}) (int64, error) {
+	if s, ok := v.(string); ok {
+		return ParseInteger(s)
+	}
+	return AsInteger(v)
+}
+
+// AsNumberLenient converts the value into a number, parsing strings with
+// ParseNumber.
+//
+// Values which are not strings are converted as with AsNumber.
+func AsNumberLenient(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		return ParseNumber(s)
+	}
+	return AsNumber(v)
+}
+
+// IsNullLenient checks that the value is null, parsing strings with
+// ParseNull.
+func IsNullLenient(v interface{}) error {
+	if s, ok := v.(string); ok {
+		return ParseNull(s)
+	}
+	return Is(v, TypeNull)
+}
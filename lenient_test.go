@@ -0,0 +1,102 @@
+package maputil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestParseBoolean(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"true", "TRUE", "Yes", "on", "1"} {
+		b, err := maputil.ParseBoolean(s)
+		require.NoError(t, err, s)
+		require.True(t, b, s)
+	}
+	for _, s := range []string{"false", "False", "no", "OFF", "0"} {
+		b, err := maputil.ParseBoolean(s)
+		require.NoError(t, err, s)
+		require.False(t, b, s)
+	}
+	for _, s := range []string{"", " true", "t", "2", "enabled"} {
+		_, err := maputil.ParseBoolean(s)
+		require.Equal(t, maputil.ParseError{Value: s, Type: maputil.TypeBoolean}, err, s)
+	}
+}
+
+func TestParseInteger(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int64{"0": 0, "8080": 8080, "-12": -12, "9223372036854775807": 1<<63 - 1}
+	for s, expected := range tests {
+		i, err := maputil.ParseInteger(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, i, s)
+	}
+	for _, s := range []string{"", "+1", "01", "1.0", "1e3", "0x10", "1_000", " 1", "-"} {
+		_, err := maputil.ParseInteger(s)
+		require.Equal(t, maputil.ParseError{Value: s, Type: maputil.TypeInteger}, err, s)
+	}
+	_, err := maputil.ParseInteger("9223372036854775808")
+	require.EqualError(t, err, "invalid value 9223372036854775808; overflows int64")
+}
+
+func TestParseNumber(t *testing.T) {
+	t.Parallel()
+	tests := map[string]float64{"0": 0, "-1.5": -1.5, "2e3": 2000, "1.25E-2": 0.0125}
+	for s, expected := range tests {
+		f, err := maputil.ParseNumber(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, f, s)
+	}
+	for _, s := range []string{"", ".5", "1.", "NaN", "Inf", "0x1p-2", "1,5"} {
+		_, err := maputil.ParseNumber(s)
+		require.Equal(t, maputil.ParseError{Value: s, Type: maputil.TypeNumber}, err, s)
+	}
+	_, err := maputil.ParseNumber("1e400")
+	require.EqualError(t, err, "invalid value 1e400; overflows float64")
+}
+
+func TestParseNull(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, maputil.ParseNull(""))
+	require.NoError(t, maputil.ParseNull("null"))
+	require.Equal(t, maputil.ParseError{Value: "nil", Type: maputil.TypeNull}, maputil.ParseNull("nil"))
+}
+
+func TestLenientConversions(t *testing.T) {
+	t.Parallel()
+	b, err := maputil.AsBooleanLenient("yes")
+	require.NoError(t, err)
+	require.True(t, b)
+	b, err = maputil.AsBooleanLenient(true)
+	require.NoError(t, err)
+	require.True(t, b)
+	_, err = maputil.AsBooleanLenient(1)
+	require.EqualError(t, err, maputil.InvalidTypeError{
+		Expected: []string{maputil.TypeBoolean},
+		Actual:   maputil.TypeInteger,
+	}.Error())
+
+	i, err := maputil.AsIntegerLenient("8080")
+	require.NoError(t, err)
+	require.Equal(t, int64(8080), i)
+	i, err = maputil.AsIntegerLenient(testInteger)
+	require.NoError(t, err)
+	require.Equal(t, testInteger, i)
+
+	n, err := maputil.AsNumberLenient("0.5")
+	require.NoError(t, err)
+	require.Equal(t, 0.5, n)
+	n, err = maputil.AsNumberLenient(2)
+	require.NoError(t, err)
+	require.Equal(t, 2.0, n)
+
+	require.NoError(t, maputil.IsNullLenient(""))
+	require.NoError(t, maputil.IsNullLenient(nil))
+	require.Error(t, maputil.IsNullLenient(false))
+
+	// The strict conversions are unchanged.
+	_, err = maputil.AsInteger("8080")
+	require.Error(t, err)
+}
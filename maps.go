@@ -0,0 +1,117 @@
+package maputil
+
+import "sort"
+
+// AsBooleanMap attempts to coerce the value into an object whose values are
+// all booleans.
+//
+// If any value can not be converted, a KeyError is returned for the first
+// such key in sorted order.
+func AsBooleanMap(v interface{}) (map[string]bool, error) {
+	o, err := AsObject(v)
+	if err != nil {
+		return nil, err
+	}
+	bm := make(map[string]bool, len(o))
+	for _, k := range sortedKeys(o) {
+		b, err := AsBoolean(o[k])
+		if err != nil {
+			return nil, KeyError{Key: k, Err: err}
+		}
+		bm[k] = b
+	}
+	return bm, nil
+}
+
+// AsIntegerMap attempts to coerce the value into an object whose values are
+// all integers.
+//
+// If any value can not be converted, a KeyError is returned for the first
+// such key in sorted order.
+func AsIntegerMap(v interface{}) (map[string]int64, error) {
+	o, err := AsObject(v)
+	if err != nil {
+		return nil, err
+	}
+	im := make(map[string]int64, len(o))
+	for _, k := range sortedKeys(o) {
+		i, err := AsInteger(o[k])
+		if err != nil {
+			return nil, KeyError{Key: k, Err: err}
+		}
+		im[k] = i
+	}
+	return im, nil
+}
+
+// AsNumberMap attempts to coerce the value into an object whose values are
+// all numbers.
+//
+// If any value can not be converted, a KeyError is returned for the first
+// such key in sorted order.
+func AsNumberMap(v interface{}) (map[string]float64, error) {
+	o, err := AsObject(v)
+	if err != nil {
+		return nil, err
+	}
+	nm := make(map[string]float64, len(o))
+	for _, k := range sortedKeys(o) {
+		n, err := AsNumber(o[k])
+		if err != nil {
+			return nil, KeyError{Key: k, Err: err}
+		}
+		nm[k] = n
+	}
+	return nm, nil
+}
+
+// AsObjectMap attempts to coerce the value into an object whose values are
+// all objects.
+//
+// If any value can not be converted, a KeyError is returned for the first
+// such key in sorted order. The inner objects are not copied.
+func AsObjectMap(v interface{}) (map[string]map[string]interface{}, error) {
+	o, err := AsObject(v)
+	if err != nil {
+		return nil, err
+	}
+	om := make(map[string]map[string]interface{}, len(o))
+	for _, k := range sortedKeys(o) {
+		obj, err := AsObject(o[k])
+		if err != nil {
+			return nil, KeyError{Key: k, Err: err}
+		}
+		om[k] = obj
+	}
+	return om, nil
+}
+
+// AsStringMap attempts to coerce the value into an object whose values are
+// all strings.
+//
+// If any value can not be converted, a KeyError is returned for the first
+// such key in sorted order.
+func AsStringMap(v interface{}) (map[string]string, error) {
+	o, err := AsObject(v)
+	if err != nil {
+		return nil, err
+	}
+	sm := make(map[string]string, len(o))
+	for _, k := range sortedKeys(o) {
+		s, err := AsString(o[k])
+		if err != nil {
+			return nil, KeyError{Key: k, Err: err}
+		}
+		sm[k] = s
+	}
+	return sm, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package maputil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsTypedMaps(t *testing.T) {
+	t.Parallel()
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		sm, err := maputil.AsStringMap(map[string]interface{}{"app": "web", "tier": "front"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"app": "web", "tier": "front"}, sm)
+
+		im, err := maputil.AsIntegerMap(map[string]interface{}{"a": 1, "b": 2.0})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"a": 1, "b": 2}, im)
+
+		nm, err := maputil.AsNumberMap(map[string]interface{}{"a": 1, "b": 0.5})
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"a": 1, "b": 0.5}, nm)
+
+		bm, err := maputil.AsBooleanMap(map[string]interface{}{"a": true})
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"a": true}, bm)
+
+		inner := map[string]interface{}{"host": "db"}
+		om, err := maputil.AsObjectMap(map[string]interface{}{"primary": inner})
+		require.NoError(t, err)
+		require.Equal(t, map[string]map[string]interface{}{"primary": inner}, om)
+
+		sm, err = maputil.AsStringMap(map[string]interface{}{})
+		require.NoError(t, err)
+		require.Empty(t, sm)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := maputil.AsStringMap(map[string]interface{}{"c": 1, "b": true, "a": "ok"})
+		require.EqualError(t, err, `key "b": invalid type boolean; expected string`)
+		var keyErr maputil.KeyError
+		require.True(t, errors.As(err, &keyErr))
+		require.Equal(t, "b", keyErr.Key)
+		require.True(t, errors.Is(err, maputil.ErrInvalidType))
+
+		_, err = maputil.AsIntegerMap(map[string]interface{}{"a": 1.5})
+		require.EqualError(t, err, `key "a": invalid type number; expected integer`)
+		_, err = maputil.AsObjectMap([]interface{}{})
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeObject},
+			Actual:   maputil.TypeArray,
+		}.Error())
+	})
+}
+
+func TestMapAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		keyGood:   map[string]interface{}{"a": "1"},
+		keyBad:    testString,
+		keyBadVal: map[string]interface{}{"a": 1},
+	}
+
+	sm, err := maputil.RequireStringMap(m, keyGood)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "1"}, sm)
+	_, err = maputil.RequireStringMap(m, keyMissing)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+
+	dv := map[string]int64{"b": 2}
+	im, err := maputil.OptionalIntegerMap(m, keyGood, dv)
+	require.Error(t, err)
+	require.Equal(t, dv, im)
+	im, err = maputil.OptionalIntegerMap(m, keyBadVal, dv)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"a": 1}, im)
+
+	_, ok, err := maputil.GetBooleanMap(m, keyBad)
+	require.True(t, ok)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+	_, ok, err = maputil.GetObjectMap(m, keyMissing)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -59,6 +59,7 @@ func (p *Path) Clear() *Path {
 func (p *Path) Copy() *Path {
 	if len(p.Elements) == 0 {
 		return &Path{
+			Filename: p.Filename,
 			Style:    p.Style,
 			Elements: nil,
 		}
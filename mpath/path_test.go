@@ -109,6 +109,12 @@ func TestPath(t *testing.T) {
 			c.Add(mpath.Key("one"))
 			require.NotEqual(t, c, p)
 		})
+		t.Run("EmptyWithFile", func(t *testing.T) {
+			t.Parallel()
+			p := mpath.New(dn)
+			p.Filename = "file.json"
+			require.Equal(t, p, p.Copy())
+		})
 		t.Run("NonEmpty", func(t *testing.T) {
 			t.Parallel()
 			p := mpath.New(dn, mpath.Key("one"), mpath.Index(2), mpath.RangeEmpty())
@@ -0,0 +1,487 @@
+package maputil
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/tvarney/maputil/consterr"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// errUnsupportedElement is the error of a path passed to At which contains an
+// element other than a key or an index.
+const errUnsupportedElement consterr.Error = "only keys and indices are supported"
+
+// consumed holds the values deleted from their objects by a context with
+// Consume set, so that they remain readable through the wrappers which read
+// them; without it, reading "server.host" would delete "server" and a later
+// read of "server.port" would find it missing.
+type consumed struct {
+	mu     sync.Mutex
+	values map[uintptr]map[string]interface{}
+}
+
+func newConsumed() *consumed {
+	return &consumed{values: map[uintptr]map[string]interface{}{}}
+}
+
+func (c *consumed) get(m map[string]interface{}, key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.values[reflect.ValueOf(m).Pointer()][key]
+	return v, ok
+}
+
+func (c *consumed) put(m map[string]interface{}, key string, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := reflect.ValueOf(m).Pointer()
+	values, ok := c.values[id]
+	if !ok {
+		values = map[string]interface{}{}
+		c.values[id] = values
+	}
+	values[key] = v
+}
+
+// node holds the error context of a wrapped value and the path of the value
+// relative to the path of the context.
+type node struct {
+	ctx      *errctx.Context
+	path     []mpath.Element
+	consumed *consumed
+
+	// failed is set once an error has been reported for the value or one of
+	// its parents, so that the values below it are not reported as missing.
+	failed bool
+}
+
+// child returns the node of the value at the given element below this node.
+func (n node) child(elem mpath.Element) node {
+	path := make([]mpath.Element, len(n.path)+1)
+	copy(path, n.path)
+	path[len(n.path)] = elem
+	return node{ctx: n.ctx, path: path, consumed: n.consumed, failed: n.failed}
+}
+
+// report sends the error to the context at the path of this node.
+func (n node) report(err error) {
+	defer n.ctx.Leave(n.ctx.Enter(n.path...))
+	n.ctx.Error(err)
+}
+
+// Object wraps a map, providing chained access to its values with errors sent
+// to an error context.
+//
+// An Object does not copy the map it wraps. Keys read through it are passed to
+// ctx.UseKey, so they are tracked or consumed according to the settings of the
+// context; a consumed value remains readable through the Object it was read
+// from and the wrappers derived from it, so that sibling keys of a nested
+// object may be read one at a time. Errors are reported at the path of the
+// value relative to the path of the context; objects and arrays of the wrong
+// type are reported when they are read, while typed values are checked when
+// their Value method is called. A missing object is not an error until a value
+// is required from it.
+type Object struct {
+	node
+	m  map[string]interface{}
+	ok bool
+}
+
+// NewObject wraps the given map, sending errors to the given context.
+func NewObject(ctx *errctx.Context, m map[string]interface{}) Object {
+	return Object{node: node{ctx: ctx, consumed: newConsumed()}, m: m, ok: true}
+}
+
+// Present returns true if the object was present.
+func (o Object) Present() bool {
+	return o.ok
+}
+
+// Map returns the wrapped map, or nil if the object was missing or invalid.
+func (o Object) Map() map[string]interface{} {
+	return o.m
+}
+
+// Get returns the value of the given key.
+func (o Object) Get(key string) Value {
+	n := o.child(mpath.Key(key))
+	if !o.ok {
+		return Value{node: n}
+	}
+	v, ok := o.m[key]
+	if !ok {
+		v, ok = o.consumed.get(o.m, key)
+	}
+	o.ctx.UseKey(o.m, key)
+	if ok && o.ctx.Consume {
+		o.consumed.put(o.m, key, v)
+	}
+	return Value{node: n, v: v, ok: ok}
+}
+
+// At returns the value at the given dot-notation path below this object, such
+// as "server.ports[0]".
+func (o Object) At(path string) Value {
+	return Value{node: o.node, v: o.m, ok: o.ok}.At(path)
+}
+
+// Array returns the value of the given key as an array.
+func (o Object) Array(key string) Array {
+	return o.Get(key).Array()
+}
+
+// Boolean returns the value of the given key as a boolean.
+func (o Object) Boolean(key string) BooleanValue {
+	return o.Get(key).Boolean()
+}
+
+// Integer returns the value of the given key as an integer.
+func (o Object) Integer(key string) IntegerValue {
+	return o.Get(key).Integer()
+}
+
+// Number returns the value of the given key as a number.
+func (o Object) Number(key string) NumberValue {
+	return o.Get(key).Number()
+}
+
+// Object returns the value of the given key as an object.
+func (o Object) Object(key string) Object {
+	return o.Get(key).Object()
+}
+
+// String returns the value of the given key as a string.
+func (o Object) String(key string) StringValue {
+	return o.Get(key).String()
+}
+
+// Array wraps an array, providing chained access to its elements with errors
+// sent to an error context.
+//
+// An Array does not copy the array it wraps, and reports errors in the same
+// way as an Object.
+type Array struct {
+	node
+	a  []interface{}
+	ok bool
+}
+
+// NewArray wraps the given array, sending errors to the given context.
+func NewArray(ctx *errctx.Context, a []interface{}) Array {
+	return Array{node: node{ctx: ctx, consumed: newConsumed()}, a: a, ok: true}
+}
+
+// Present returns true if the array was present.
+func (a Array) Present() bool {
+	return a.ok
+}
+
+// Slice returns the wrapped array, or nil if the array was missing or invalid.
+func (a Array) Slice() []interface{} {
+	return a.a
+}
+
+// Len returns the length of the array.
+func (a Array) Len() int {
+	return len(a.a)
+}
+
+// Index returns the element at the given index.
+//
+// An index outside of the array is treated as a missing value.
+func (a Array) Index(i int) Value {
+	n := a.child(mpath.Index(i))
+	if i < 0 || i >= len(a.a) {
+		return Value{node: n}
+	}
+	return Value{node: n, v: a.a[i], ok: true}
+}
+
+// At returns the value at the given dot-notation path below this array, such
+// as "[0].name".
+func (a Array) At(path string) Value {
+	return Value{node: a.node, v: a.a, ok: a.ok}.At(path)
+}
+
+// Array returns the element at the given index as an array.
+func (a Array) Array(i int) Array {
+	return a.Index(i).Array()
+}
+
+// Boolean returns the element at the given index as a boolean.
+func (a Array) Boolean(i int) BooleanValue {
+	return a.Index(i).Boolean()
+}
+
+// Integer returns the element at the given index as an integer.
+func (a Array) Integer(i int) IntegerValue {
+	return a.Index(i).Integer()
+}
+
+// Number returns the element at the given index as a number.
+func (a Array) Number(i int) NumberValue {
+	return a.Index(i).Number()
+}
+
+// Object returns the element at the given index as an object.
+func (a Array) Object(i int) Object {
+	return a.Index(i).Object()
+}
+
+// String returns the element at the given index as a string.
+func (a Array) String(i int) StringValue {
+	return a.Index(i).String()
+}
+
+// Value is a single value read through an Object or Array.
+type Value struct {
+	node
+	v  interface{}
+	ok bool
+}
+
+// Present returns true if the value was present.
+func (v Value) Present() bool {
+	return v.ok
+}
+
+// Raw returns the underlying value, or nil if the value was missing.
+func (v Value) Raw() interface{} {
+	return v.v
+}
+
+// At returns the value at the given dot-notation path below this value.
+//
+// Only key and index elements are supported; a path which can not be parsed
+// or which contains other elements is reported as a PathError.
+func (v Value) At(path string) Value {
+	elems, err := mpath.DotNotation{}.Parse(path)
+	if err != nil {
+		return v.badPath(path, err)
+	}
+	for _, e := range elems {
+		switch e.(type) {
+		case mpath.Key, mpath.Index:
+		default:
+			return v.badPath(path, errUnsupportedElement)
+		}
+	}
+	for _, e := range elems {
+		if k, ok := e.(mpath.Key); ok {
+			v = v.Object().Get(string(k))
+		} else {
+			v = v.Array().Index(int(e.(mpath.Index)))
+		}
+	}
+	return v
+}
+
+func (v Value) badPath(path string, err error) Value {
+	if !v.failed {
+		v.report(PathError{Path: path, Err: err})
+	}
+	return Value{node: node{ctx: v.ctx, path: v.path, consumed: v.consumed, failed: true}}
+}
+
+// Array returns the value as an array.
+//
+// An error is reported if the value is present but is not an array.
+func (v Value) Array() Array {
+	a := Array{node: v.node}
+	if !v.ok {
+		return a
+	}
+	arr, err := AsArray(v.v)
+	if err != nil {
+		v.report(err)
+		a.failed = true
+		return a
+	}
+	a.a, a.ok = arr, true
+	return a
+}
+
+// Object returns the value as an object.
+//
+// An error is reported if the value is present but is not an object.
+func (v Value) Object() Object {
+	o := Object{node: v.node}
+	if !v.ok {
+		return o
+	}
+	m, err := AsObject(v.v)
+	if err != nil {
+		v.report(err)
+		o.failed = true
+		return o
+	}
+	o.m, o.ok = m, true
+	return o
+}
+
+// Boolean returns the value as a boolean.
+func (v Value) Boolean() BooleanValue {
+	return BooleanValue{v: v}
+}
+
+// Integer returns the value as an integer.
+func (v Value) Integer() IntegerValue {
+	return IntegerValue{v: v}
+}
+
+// Number returns the value as a number.
+func (v Value) Number() NumberValue {
+	return NumberValue{v: v}
+}
+
+// String returns the value as a string.
+func (v Value) String() StringValue {
+	return StringValue{v: v}
+}
+
+// present returns true if the value is present, reporting a missing required
+// value if it is not optional.
+func (v Value) present(optional bool) bool {
+	if v.ok {
+		return true
+	}
+	if !optional && !v.failed {
+		key := ""
+		if len(v.path) > 0 {
+			key = v.path[len(v.path)-1].String()
+		}
+		v.report(MissingRequiredValueError{Key: key})
+	}
+	return false
+}
+
+// BooleanValue is a value which is converted to a boolean by its Value method.
+type BooleanValue struct {
+	v        Value
+	dv       bool
+	optional bool
+}
+
+// Default makes the value optional, returning the given value if it is
+// missing or invalid.
+func (b BooleanValue) Default(dv bool) BooleanValue {
+	b.dv, b.optional = dv, true
+	return b
+}
+
+// Value converts the value to a boolean, sending any errors to the context.
+//
+// Strings are parsed with ParseBoolean if the context is lenient.
+func (b BooleanValue) Value() bool {
+	if !b.v.present(b.optional) {
+		return b.dv
+	}
+	conv := AsBoolean
+	if b.v.ctx.Lenient {
+		conv = AsBooleanLenient
+	}
+	r, err := conv(b.v.v)
+	if err != nil {
+		b.v.report(err)
+		return b.dv
+	}
+	return r
+}
+
+// IntegerValue is a value which is converted to an integer by its Value
+// method.
+type IntegerValue struct {
+	v        Value
+	dv       int64
+	optional bool
+}
+
+// Default makes the value optional, returning the given value if it is
+// missing or invalid.
+func (i IntegerValue) Default(dv int64) IntegerValue {
+	i.dv, i.optional = dv, true
+	return i
+}
+
+// Value converts the value to an integer, sending any errors to the context.
+//
+// Strings are parsed with ParseInteger if the context is lenient.
+func (i IntegerValue) Value() int64 {
+	if !i.v.present(i.optional) {
+		return i.dv
+	}
+	conv := AsInteger
+	if i.v.ctx.Lenient {
+		conv = AsIntegerLenient
+	}
+	r, err := conv(i.v.v)
+	if err != nil {
+		i.v.report(err)
+		return i.dv
+	}
+	return r
+}
+
+// NumberValue is a value which is converted to a number by its Value method.
+type NumberValue struct {
+	v        Value
+	dv       float64
+	optional bool
+}
+
+// Default makes the value optional, returning the given value if it is
+// missing or invalid.
+func (n NumberValue) Default(dv float64) NumberValue {
+	n.dv, n.optional = dv, true
+	return n
+}
+
+// Value converts the value to a number, sending any errors to the context.
+//
+// Strings are parsed with ParseNumber if the context is lenient.
+func (n NumberValue) Value() float64 {
+	if !n.v.present(n.optional) {
+		return n.dv
+	}
+	conv := AsNumber
+	if n.v.ctx.Lenient {
+		conv = AsNumberLenient
+	}
+	r, err := conv(n.v.v)
+	if err != nil {
+		n.v.report(err)
+		return n.dv
+	}
+	return r
+}
+
+// StringValue is a value which is converted to a string by its Value method.
+type StringValue struct {
+	v        Value
+	dv       string
+	optional bool
+}
+
+// Default makes the value optional, returning the given value if it is
+// missing or invalid.
+func (s StringValue) Default(dv string) StringValue {
+	s.dv, s.optional = dv, true
+	return s
+}
+
+// Value converts the value to a string, sending any errors to the context.
+func (s StringValue) Value() string {
+	if !s.v.present(s.optional) {
+		return s.dv
+	}
+	r, err := AsString(s.v.v)
+	if err != nil {
+		s.v.report(err)
+		return s.dv
+	}
+	return r
+}
@@ -0,0 +1,160 @@
+package maputil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+func TestObject(t *testing.T) {
+	t.Parallel()
+	newMap := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name": "app",
+			"server": map[string]interface{}{
+				"host":  "localhost",
+				"port":  8080,
+				"ratio": 0.5,
+				"tls":   true,
+			},
+			"listeners": []interface{}{
+				map[string]interface{}{"port": 80},
+				map[string]interface{}{"port": "https"},
+			},
+			"bad": 1,
+		}
+	}
+	lines := func(sb *strings.Builder) []string {
+		return strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		obj := maputil.NewObject(ctx, newMap())
+		require.True(t, obj.Present())
+		require.Equal(t, "app", obj.String("name").Value())
+		server := obj.Object("server")
+		require.True(t, server.Present())
+		require.Equal(t, "localhost", server.String("host").Value())
+		require.Equal(t, int64(8080), server.Integer("port").Default(80).Value())
+		require.Equal(t, 0.5, server.Number("ratio").Value())
+		require.True(t, server.Boolean("tls").Value())
+		require.Equal(t, 2, obj.Array("listeners").Len())
+		require.Equal(t, int64(80), obj.Array("listeners").Object(0).Integer("port").Value())
+		require.Equal(t, int64(80), obj.At("listeners[0].port").Integer().Value())
+		require.Equal(t, int64(8080), obj.At("server.port").Integer().Value())
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("ZeroCopy", func(t *testing.T) {
+		t.Parallel()
+		m := newMap()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		server := maputil.NewObject(ctx, m).Object("server").Map()
+		server["port"] = 9090
+		require.Equal(t, 9090, m["server"].(map[string]interface{})["port"])
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		obj := maputil.NewObject(ctx, newMap())
+		require.False(t, obj.Object("missing").Present())
+		require.Equal(t, int64(80), obj.Object("missing").Integer("port").Default(80).Value())
+		require.Equal(t, "x", obj.At("missing.a[3]").String().Default("x").Value())
+		require.Equal(t, 1.5, obj.Array("listeners").Number(5).Default(1.5).Value())
+		require.True(t, obj.Boolean("missing").Default(true).Value())
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Path.Add(mpath.Key("root"))
+		obj := maputil.NewObject(ctx, newMap())
+		require.Zero(t, obj.Integer("name").Value())
+		require.Equal(t, int64(443), obj.At("listeners[1].port").Integer().Default(443).Value())
+		require.Zero(t, obj.Object("server").Integer("missing").Value())
+		require.Empty(t, obj.Array("listeners").Object(2).String("name").Value())
+		require.Equal(t, "x", obj.Object("bad").String("host").Default("x").Value())
+		require.Empty(t, obj.Object("bad").String("host").Value())
+		require.False(t, obj.At("a[").Present())
+		require.False(t, obj.At("listeners[1:]").Present())
+		require.Equal(t, []string{
+			`root.name: invalid type string; expected integer`,
+			`root.listeners[1].port: invalid type string; expected integer`,
+			`root.server.missing: missing required value "missing"`,
+			`root.listeners[2].name: missing required value "name"`,
+			`root.bad: invalid type integer; expected object`,
+			`root.bad: invalid type integer; expected object`,
+			`root: invalid path "a[": unmatched open bracket '['`,
+			`root: invalid path "listeners[1:]": only keys and indices are supported`,
+		}, lines(sb))
+		require.Equal(t, "root", ctx.Path.String())
+	})
+	t.Run("Lenient", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Lenient = true
+		obj := maputil.NewObject(ctx, map[string]interface{}{"port": "80", "debug": "yes", "ratio": "0.5"})
+		require.Equal(t, int64(80), obj.Integer("port").Value())
+		require.True(t, obj.Boolean("debug").Value())
+		require.Equal(t, 0.5, obj.Number("ratio").Value())
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Consume", func(t *testing.T) {
+		t.Parallel()
+		m := newMap()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Consume = true
+		obj := maputil.NewObject(ctx, m)
+		require.Equal(t, "app", obj.String("name").Value())
+		require.Equal(t, int64(8080), obj.At("server.port").Integer().Value())
+		require.NotContains(t, m, "name")
+		require.NotContains(t, m, "server")
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("ConsumeSiblings", func(t *testing.T) {
+		t.Parallel()
+		m := newMap()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Consume = true
+		obj := maputil.NewObject(ctx, m)
+		require.Equal(t, int64(8080), obj.Object("server").Integer("port").Value())
+		require.Equal(t, "localhost", obj.Object("server").String("host").Value())
+		require.Equal(t, 0.5, obj.At("server.ratio").Number().Value())
+		require.True(t, obj.At("server.tls").Boolean().Value())
+		require.Equal(t, int64(80), obj.At("listeners[0].port").Integer().Value())
+		require.Equal(t, "https", obj.At("listeners[1].port").String().Value())
+		require.Equal(t, 2, obj.Array("listeners").Len())
+		require.Equal(t, "app", obj.String("name").Value())
+		require.Equal(t, "app", obj.String("name").Default("x").Value())
+		require.Empty(t, sb.String())
+		require.Equal(t, map[string]interface{}{"bad": 1}, m)
+
+		require.Zero(t, obj.Integer("port").Value())
+		require.Equal(t, "port: missing required value \"port\"\n", sb.String())
+	})
+}
+
+func TestArray(t *testing.T) {
+	t.Parallel()
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	arr := maputil.NewArray(ctx, []interface{}{"a", []interface{}{1, 2}, true})
+	require.True(t, arr.Present())
+	require.Equal(t, 3, arr.Len())
+	require.Equal(t, "a", arr.String(0).Value())
+	require.Equal(t, int64(2), arr.Array(1).Integer(1).Value())
+	require.Equal(t, int64(1), arr.At("[1][0]").Integer().Value())
+	require.True(t, arr.Boolean(2).Value())
+	require.Equal(t, true, arr.Index(2).Raw())
+	require.False(t, arr.Index(-1).Present())
+	require.Zero(t, arr.Number(0).Value())
+	require.Equal(t, "invalid type string; expected number", ctx.LastError().Error())
+	require.Equal(t, "[0]: invalid type string; expected number\n", sb.String())
+}
@@ -0,0 +1,170 @@
+// Package pack converts Go values into JSON-like maps.
+//
+// It is the reverse of unpack.Decode and honors the same `map` struct tags.
+package pack
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/consterr"
+	"github.com/tvarney/maputil/internal/tags"
+)
+
+// ErrUnsupportedType is the root error of a Go type which can not be encoded.
+const ErrUnsupportedType consterr.Error = "unsupported type"
+
+// UnsupportedTypeError is an error indicating that a value could not be
+// represented as a JSON-like value.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+// Error returns the string representation of this unsupported type error.
+func (e UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%s %s", string(ErrUnsupportedType), e.Type)
+}
+
+// Unwrap returns the parent error for this unsupported type error.
+func (e UnsupportedTypeError) Unwrap() error {
+	return ErrUnsupportedType
+}
+
+// Encode converts a struct, or a map with string keys, into an object.
+//
+// Struct fields are named using the `map` struct tag in the same way as
+// unpack.Decode; fields tagged with the `omitempty` option are left out of the
+// result if they hold a zero value or an empty slice or map. The `required`,
+// `enum` and `default` options are ignored.
+//
+// Values are converted to the canonical types understood by maputil.TypeName:
+// signed integers become int64, unsigned integers become int64 unless they are
+// too large, in which case they are kept as uint64, floating point values
+// become float64, and slices and arrays become []interface{}. Values which
+// implement maputil.GenericNumber, such as json.Number, are kept as is so
+// that no precision is lost.
+func Encode(v interface{}) (map[string]interface{}, error) {
+	r, err := EncodeValue(v)
+	if err != nil {
+		return nil, err
+	}
+	m, err := maputil.AsObject(r)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EncodeValue converts any supported Go value into a JSON-like value.
+func EncodeValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return encodeValue(reflect.ValueOf(v))
+}
+
+func encodeValue(rv reflect.Value) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+	default:
+		if rv.Type().Implements(genericNumberType) {
+			return rv.Interface(), nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(rv.Elem())
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return encodeArray(rv)
+	case reflect.Array:
+		return encodeArray(rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, UnsupportedTypeError{Type: rv.Type()}
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return encodeMap(rv)
+	case reflect.Struct:
+		return encodeStruct(rv)
+	}
+	return nil, UnsupportedTypeError{Type: rv.Type()}
+}
+
+var genericNumberType = reflect.TypeOf((*maputil.GenericNumber)(nil)).Elem()
+
+func encodeArray(rv reflect.Value) ([]interface{}, error) {
+	a := make([]interface{}, rv.Len())
+	for i := range a {
+		v, err := encodeValue(rv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func encodeMap(rv reflect.Value) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		v, err := encodeValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		m[iter.Key().String()] = v
+	}
+	return m, nil
+}
+
+func encodeStruct(rv reflect.Value) (map[string]interface{}, error) {
+	fields := tags.Fields(rv.Type())
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		fv := rv.FieldByIndex(f.Index)
+		if f.OmitEmpty && isEmpty(fv) {
+			continue
+		}
+		v, err := encodeValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		m[f.Name] = v
+	}
+	return m, nil
+}
+
+// isEmpty returns true if the value should be left out by the omitempty
+// option.
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
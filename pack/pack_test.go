@@ -0,0 +1,163 @@
+package pack_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/pack"
+	"github.com/tvarney/maputil/unpack"
+)
+
+type packTLS struct {
+	Enabled bool   `map:"enabled"`
+	Cert    string `map:"cert,omitempty"`
+}
+
+type packBase struct {
+	ID string `map:"id"`
+}
+
+type packServer struct {
+	packBase
+	Host    string            `map:"host,default=localhost"`
+	Port    uint16            `map:"port,required"`
+	Ratio   float32           `map:"ratio,omitempty"`
+	Tags    []string          `map:"tags,omitempty"`
+	Labels  map[string]string `map:"labels,omitempty"`
+	TLS     *packTLS          `map:"tls"`
+	Big     uint64            `map:"big,omitempty"`
+	Number  json.Number       `map:"number,omitempty"`
+	Extra   interface{}       `map:"extra,omitempty"`
+	Ignored string            `map:"-"`
+	Plain   int8
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	t.Run("Struct", func(t *testing.T) {
+		t.Parallel()
+		s := packServer{
+			packBase: packBase{ID: "a"},
+			Host:     "example.com",
+			Port:     443,
+			Tags:     []string{"x"},
+			TLS:      &packTLS{Enabled: true},
+			Big:      math.MaxUint64,
+			Number:   json.Number("123456789012345678901234567890"),
+			Extra:    map[string]interface{}{"k": []int{1}},
+			Ignored:  "ignored",
+			Plain:    -3,
+		}
+		m, err := pack.Encode(s)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"id":     "a",
+			"host":   "example.com",
+			"port":   int64(443),
+			"tags":   []interface{}{"x"},
+			"tls":    map[string]interface{}{"enabled": true},
+			"big":    uint64(math.MaxUint64),
+			"number": json.Number("123456789012345678901234567890"),
+			"extra":  map[string]interface{}{"k": []interface{}{int64(1)}},
+			"Plain":  int64(-3),
+		}, m)
+	})
+	t.Run("Pointer", func(t *testing.T) {
+		t.Parallel()
+		m, err := pack.Encode(&packTLS{Enabled: true, Cert: "c"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"enabled": true, "cert": "c"}, m)
+	})
+	t.Run("NilFields", func(t *testing.T) {
+		t.Parallel()
+		m, err := pack.Encode(packServer{})
+		require.NoError(t, err)
+		require.Nil(t, m["tls"])
+		require.Contains(t, m, "tls")
+		require.NotContains(t, m, "tags")
+	})
+	t.Run("Map", func(t *testing.T) {
+		t.Parallel()
+		m, err := pack.Encode(map[string]float32{"a": 1.5})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": float64(1.5)}, m)
+	})
+	t.Run("NotObject", func(t *testing.T) {
+		t.Parallel()
+		_, err := pack.Encode([]int{1})
+		require.True(t, errors.Is(err, maputil.ErrInvalidType))
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Parallel()
+		_, err := pack.Encode(map[string]interface{}{"c": make(chan int)})
+		require.EqualError(t, err, "unsupported type chan int")
+		require.True(t, errors.Is(err, pack.ErrUnsupportedType))
+
+		_, err = pack.Encode(map[int]string{1: "a"})
+		require.True(t, errors.Is(err, pack.ErrUnsupportedType))
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+		s := packServer{
+			packBase: packBase{ID: "a"},
+			Host:     "h",
+			Port:     80,
+			Ratio:    0.25,
+			Labels:   map[string]string{"k": "v"},
+			TLS:      &packTLS{Enabled: true, Cert: "c"},
+			Big:      math.MaxInt64,
+			Plain:    1,
+		}
+		m, err := pack.Encode(s)
+		require.NoError(t, err)
+		for k, v := range m {
+			require.NotContains(t, maputil.TypeName(v), "golang", k)
+		}
+
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		r := packServer{}
+		unpack.Decode(ctx, m, &r)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, s, r)
+	})
+}
+
+func TestEncodeValue(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		in       interface{}
+		expected interface{}
+	}{
+		{"Nil", nil, nil},
+		{"Bool", true, true},
+		{"Int", int32(-1), int64(-1)},
+		{"Uint", uint8(1), int64(1)},
+		{"Float", float32(0.5), float64(0.5)},
+		{"String", "s", "s"},
+		{"NilSlice", []string(nil), nil},
+		{"Array", [2]bool{true, false}, []interface{}{true, false}},
+		{"NilPointer", (*int)(nil), nil},
+		{"NumberPointer", func() *json.Number { n := json.Number("1"); return &n }(), json.Number("1")},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := pack.EncodeValue(c.in)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, v)
+		})
+	}
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		e := pack.UnsupportedTypeError{Type: reflect.TypeOf(func() {})}
+		require.Equal(t, "unsupported type func()", e.Error())
+	})
+}
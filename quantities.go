@@ -0,0 +1,189 @@
+package maputil
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// byteUnits maps the units accepted by AsByteSize to their size in bytes.
+var byteUnits = map[string]int64{
+	"": 1, "B": 1,
+	"k": 1e3, "K": 1e3, "kB": 1e3, "KB": 1e3,
+	"M": 1e6, "MB": 1e6,
+	"G": 1e9, "GB": 1e9,
+	"T": 1e12, "TB": 1e12,
+	"P": 1e15, "PB": 1e15,
+	"E": 1e18, "EB": 1e18,
+	"Ki": 1 << 10, "KiB": 1 << 10,
+	"Mi": 1 << 20, "MiB": 1 << 20,
+	"Gi": 1 << 30, "GiB": 1 << 30,
+	"Ti": 1 << 40, "TiB": 1 << 40,
+	"Pi": 1 << 50, "PiB": 1 << 50,
+	"Ei": 1 << 60, "EiB": 1 << 60,
+}
+
+var (
+	byteSizeGrammar = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?) ?([A-Za-z]*)$`)
+	percentGrammar  = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?%$`)
+	countGrammar    = regexp.MustCompile(`^[0-9]+$`)
+)
+
+const (
+	byteSizeFormat = `byte size such as "512Mi" or "1.5GB"`
+	percentFormat  = `percentage such as "75%"`
+	rateFormat     = `rate such as "100/s" or "5/10m"`
+)
+
+// Rate is a number of events allowed per period of time.
+type Rate struct {
+	Count int64
+	Per   time.Duration
+}
+
+// PerSecond returns the rate as a number of events per second.
+func (r Rate) PerSecond() float64 {
+	return float64(r.Count) / r.Per.Seconds()
+}
+
+// String returns the rate in the form accepted by AsRate.
+func (r Rate) String() string {
+	return strconv.FormatInt(r.Count, 10) + "/" + r.Per.String()
+}
+
+// AsByteSize attempts to coerce the value into a number of bytes.
+//
+// Integers are taken as a number of bytes. Strings hold a number followed by
+// an optional unit, which is either an SI unit such as "kB", "MB" or "G", or
+// an IEC unit such as "KiB" or "Mi"; the trailing "B" is optional and a single
+// space may separate the number from the unit. Fractional numbers are
+// accepted as long as the result is a whole number of bytes, so "1.5GB" is
+// valid but "1.5B" is not. Negative sizes are rejected, and an OverflowError
+// is returned for sizes which do not fit in an int64.
+func AsByteSize(v interface{}) (int64, error) {
+	s, ok := v.(string)
+	if !ok {
+		i, err := AsInteger(v)
+		if _, ok := err.(OverflowError); ok {
+			return 0, err
+		}
+		if err != nil {
+			return 0, InvalidTypeError{
+				Expected: []string{TypeString, TypeInteger},
+				Actual:   TypeName(v),
+			}
+		}
+		if i < 0 {
+			return 0, InvalidValueError{
+				Value:    strconv.FormatInt(i, 10),
+				Expected: byteSizeFormat,
+				Reason:   "negative size",
+			}
+		}
+		return i, nil
+	}
+
+	match := byteSizeGrammar.FindStringSubmatch(s)
+	if match == nil {
+		return 0, InvalidValueError{Value: strconv.Quote(s), Expected: byteSizeFormat}
+	}
+	unit, ok := byteUnits[match[2]]
+	if !ok {
+		return 0, InvalidValueError{
+			Value:    strconv.Quote(s),
+			Expected: byteSizeFormat,
+			Reason:   "unknown unit " + strconv.Quote(match[2]),
+		}
+	}
+	// The number is scaled exactly so that large sizes do not lose precision.
+	r, _ := new(big.Rat).SetString(match[1])
+	r.Mul(r, new(big.Rat).SetInt64(unit))
+	if !r.IsInt() {
+		return 0, InvalidValueError{
+			Value:    strconv.Quote(s),
+			Expected: byteSizeFormat,
+			Reason:   "not a whole number of bytes",
+		}
+	}
+	if !r.Num().IsInt64() {
+		return 0, OverflowError{Value: strconv.Quote(s), Type: "int64"}
+	}
+	return r.Num().Int64(), nil
+}
+
+// AsPercent attempts to coerce the value into a fraction.
+//
+// Strings must be a number followed by a percent sign, such as "75%" or
+// "12.5%", and are divided by 100, so "75%" results in 0.75.
+//
+// Numbers are taken to already be a fraction and are returned unchanged, so
+// 0.75 also results in 0.75. Since a bare 75 almost certainly means 75% rather
+// than 7500%, numbers outside of the range -1 to 1 are rejected; values such
+// as 150% must be written as strings.
+func AsPercent(v interface{}) (float64, error) {
+	s, ok := v.(string)
+	if !ok {
+		f, err := AsNumber(v)
+		if err != nil {
+			return 0, InvalidTypeError{
+				Expected: []string{TypeString, TypeNumber},
+				Actual:   TypeName(v),
+			}
+		}
+		if f < -1 || f > 1 {
+			return 0, InvalidValueError{
+				Value:    formatNumber(f),
+				Expected: percentFormat,
+				Reason:   "numbers must be a fraction between -1 and 1",
+			}
+		}
+		return f, nil
+	}
+	if !percentGrammar.MatchString(s) {
+		return 0, InvalidValueError{Value: strconv.Quote(s), Expected: percentFormat}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, OverflowError{Value: strconv.Quote(s), Type: "float64"}
+	}
+	return f / 100, nil
+}
+
+// AsRate attempts to coerce the value into a rate.
+//
+// The value must be a string holding a count and a period separated by a
+// slash. The period is either a unit of time.ParseDuration, as in "100/s" or
+// "60/m", or any duration accepted by time.ParseDuration, as in "5/10m" or
+// "5/1h30m". The period must be positive.
+func AsRate(v interface{}) (Rate, error) {
+	s, err := AsString(v)
+	if err != nil {
+		return Rate{}, err
+	}
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || !countGrammar.MatchString(parts[0]) || parts[1] == "" {
+		return Rate{}, InvalidValueError{Value: strconv.Quote(s), Expected: rateFormat}
+	}
+	count, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Rate{}, OverflowError{Value: strconv.Quote(s), Type: "int64"}
+	}
+	period := parts[1]
+	if !strings.ContainsAny(period[:1], "0123456789.+-") {
+		period = "1" + period
+	}
+	per, err := time.ParseDuration(period)
+	if err != nil {
+		return Rate{}, rateError(s, "invalid period")
+	}
+	if per <= 0 {
+		return Rate{}, rateError(s, "period must be positive")
+	}
+	return Rate{Count: count, Per: per}, nil
+}
+
+func rateError(s, reason string) InvalidValueError {
+	return InvalidValueError{Value: strconv.Quote(s), Expected: rateFormat, Reason: reason}
+}
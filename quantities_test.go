@@ -0,0 +1,167 @@
+package maputil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsByteSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data     interface{}
+		expected int64
+	}{
+		{1024, 1024},
+		{json.Number("10"), 10},
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"1k", 1000},
+		{"1.5GB", 1500000000},
+		{"2 MB", 2000000},
+		{"512Mi", 512 << 20},
+		{"1.5KiB", 1536},
+		{"7Ei", 7 << 60},
+		{"1EB", 1e18},
+	}
+	for _, tc := range tests {
+		i, err := maputil.AsByteSize(tc.data)
+		require.NoError(t, err, tc.data)
+		require.Equal(t, tc.expected, i, tc.data)
+	}
+
+	invalid := map[interface{}]string{
+		"":     `invalid value ""; expected byte size such as "512Mi" or "1.5GB"`,
+		"-1K":  `invalid value "-1K"; expected byte size such as "512Mi" or "1.5GB"`,
+		"1.5B": `invalid value "1.5B" (not a whole number of bytes); expected byte size such as "512Mi" or "1.5GB"`,
+		"10XB": `invalid value "10XB" (unknown unit "XB"); expected byte size such as "512Mi" or "1.5GB"`,
+		"8EiB": `invalid value "8EiB"; overflows int64`,
+		-1:     `invalid value -1 (negative size); expected byte size such as "512Mi" or "1.5GB"`,
+		true:   `invalid type boolean; expected string or integer`,
+		1.5:    `invalid type number; expected string or integer`,
+		1e19:   `invalid value 1e+19; overflows int64`,
+	}
+	for v, msg := range invalid {
+		_, err := maputil.AsByteSize(v)
+		require.EqualError(t, err, msg, v)
+	}
+}
+
+func TestAsPercent(t *testing.T) {
+	t.Parallel()
+	tests := map[interface{}]float64{
+		"75%":   0.75,
+		"12.5%": 0.125,
+		"0%":    0,
+		"-10%":  -0.1,
+		"150%":  1.5,
+		0.25:    0.25,
+		1:       1,
+	}
+	for v, expected := range tests {
+		f, err := maputil.AsPercent(v)
+		require.NoError(t, err, v)
+		require.InDelta(t, expected, f, 1e-12, v)
+	}
+
+	for _, s := range []string{"75", "75 %", "%", "seventy%"} {
+		_, err := maputil.AsPercent(s)
+		require.EqualError(t, err, maputil.InvalidValueError{
+			Value:    `"` + s + `"`,
+			Expected: `percentage such as "75%"`,
+		}.Error())
+	}
+	for _, n := range []interface{}{75, 1.5, -2} {
+		_, err := maputil.AsPercent(n)
+		require.True(t, errors.Is(err, maputil.ErrInvalidValue), n)
+	}
+	_, err := maputil.AsPercent(75)
+	require.EqualError(t, err, `invalid value 75 (numbers must be a fraction between -1 and 1); `+
+		`expected percentage such as "75%"`)
+	_, err = maputil.AsPercent(nil)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+}
+
+func TestAsRate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]maputil.Rate{
+		"100/s":   {Count: 100, Per: time.Second},
+		"60/m":    {Count: 60, Per: time.Minute},
+		"5/10m":   {Count: 5, Per: 10 * time.Minute},
+		"1/.5s":   {Count: 1, Per: 500 * time.Millisecond},
+		"0/h":     {Count: 0, Per: time.Hour},
+		"10/1ms":  {Count: 10, Per: time.Millisecond},
+		"5/10m0s": {Count: 5, Per: 10 * time.Minute},
+		"5/1h30m": {Count: 5, Per: 90 * time.Minute},
+		"3/1m30s": {Count: 3, Per: 90 * time.Second},
+		"2/µs":    {Count: 2, Per: time.Microsecond},
+	}
+	for s, expected := range tests {
+		r, err := maputil.AsRate(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, r, s)
+	}
+	require.Equal(t, 0.5, maputil.Rate{Count: 30, Per: time.Minute}.PerSecond())
+	require.Equal(t, "5/10m0s", maputil.Rate{Count: 5, Per: 10 * time.Minute}.String())
+	for _, r := range []maputil.Rate{{Count: 5, Per: 10 * time.Minute}, {Count: 1, Per: 90 * time.Minute}} {
+		parsed, err := maputil.AsRate(r.String())
+		require.NoError(t, err)
+		require.Equal(t, r, parsed)
+	}
+
+	invalid := map[string]string{
+		"100":    `invalid value "100"; expected rate such as "100/s" or "5/10m"`,
+		"1.5/s":  `invalid value "1.5/s"; expected rate such as "100/s" or "5/10m"`,
+		"100/x":  `invalid value "100/x" (invalid period); expected rate such as "100/s" or "5/10m"`,
+		"100/0s": `invalid value "100/0s" (period must be positive); expected rate such as "100/s" or "5/10m"`,
+		"5/-1m":  `invalid value "5/-1m" (period must be positive); expected rate such as "100/s" or "5/10m"`,
+		"5/":     `invalid value "5/"; expected rate such as "100/s" or "5/10m"`,
+		"/s":     `invalid value "/s"; expected rate such as "100/s" or "5/10m"`,
+	}
+	for s, msg := range invalid {
+		_, err := maputil.AsRate(s)
+		require.EqualError(t, err, msg, s)
+	}
+	_, err := maputil.AsRate(100)
+	require.True(t, errors.Is(err, maputil.ErrInvalidType))
+}
+
+func TestQuantityAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{keyGood: "1Gi", keyBad: testString, keyBadVal: "150%"}
+
+	i, err := maputil.RequireByteSize(m, keyGood)
+	require.NoError(t, err)
+	require.Equal(t, int64(1<<30), i)
+	i, err = maputil.RequireByteSizeRange(m, keyGood, maputil.IntegerAtMost(1<<20))
+	require.EqualError(t, err, "invalid value 1073741824; maximum is 1048576")
+	require.Equal(t, int64(1<<30), i)
+	i, err = maputil.OptionalByteSizeRange(m, keyMissing, maputil.IntegerAtLeast(1), 64)
+	require.NoError(t, err)
+	require.Equal(t, int64(64), i)
+
+	f, err := maputil.OptionalPercentRange(m, keyBadVal, maputil.NumberBetween(0, 1), 0.5)
+	require.EqualError(t, err, "invalid value 1.5; maximum is 1")
+	require.Equal(t, 0.5, f)
+	f, ok, err := maputil.GetPercent(m, keyBadVal)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1.5, f)
+
+	_, err = maputil.RequireRate(m, keyMissing)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+	r, err := maputil.OptionalRate(m, keyBad, maputil.Rate{Count: 1, Per: time.Second})
+	require.Error(t, err)
+	require.Equal(t, int64(1), r.Count)
+
+	c := maputil.Copy(m)
+	_, ok, err = maputil.PopByteSize(c, keyGood)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NotContains(t, c, keyGood)
+}
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/jsonschema"
+)
+
+// Document returns the schema as a top-level JSON Schema document, suitable
+// for writing to a file which editors can use to check and complete
+// configuration files.
+func Document(s Schema) map[string]interface{} {
+	doc := s.JSONSchema()
+	doc["$schema"] = jsonschema.Draft
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *ObjectSchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeObject}
+	if len(s.Fields) > 0 {
+		properties := make(map[string]interface{}, len(s.Fields))
+		var required []interface{}
+		for _, f := range s.Fields {
+			properties[f.Name] = f.Schema.JSONSchema()
+			if f.Schema.IsRequired() {
+				required = append(required, f.Name)
+			}
+		}
+		doc["properties"] = properties
+		if required != nil {
+			doc["required"] = required
+		}
+	}
+	if !s.additional {
+		doc["additionalProperties"] = false
+	}
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *ArraySchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeArray}
+	if s.Items != nil {
+		doc["items"] = s.Items.JSONSchema()
+	}
+	if s.hasMin {
+		doc["minItems"] = int64(s.minItems)
+	}
+	if s.hasMax {
+		doc["maxItems"] = int64(s.maxItems)
+	}
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *AnySchema) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *NullSchema) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{"type": maputil.TypeNull}
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *BooleanSchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeBoolean}
+	if s.def != nil {
+		doc["default"] = s.def
+	}
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *IntegerSchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeInteger}
+	if s.def != nil {
+		doc["default"] = s.def
+	}
+	if s.hasMin {
+		doc[bound("minimum", "exclusiveMinimum", s.exclMin)] = s.min
+	}
+	if s.hasMax {
+		doc[bound("maximum", "exclusiveMaximum", s.exclMax)] = s.max
+	}
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *NumberSchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeNumber}
+	if s.def != nil {
+		doc["default"] = s.def
+	}
+	if s.hasMin {
+		doc[bound("minimum", "exclusiveMinimum", s.exclMin)] = s.min
+	}
+	if s.hasMax {
+		doc[bound("maximum", "exclusiveMaximum", s.exclMax)] = s.max
+	}
+	return doc
+}
+
+// JSONSchema returns the schema as a JSON Schema document.
+func (s *StringSchema) JSONSchema() map[string]interface{} {
+	doc := map[string]interface{}{"type": maputil.TypeString}
+	if s.def != nil {
+		doc["default"] = s.def
+	}
+	if s.enum != nil {
+		enum := make([]interface{}, len(s.enum))
+		for i, e := range s.enum {
+			enum[i] = e
+		}
+		doc["enum"] = enum
+	}
+	if s.hasMin {
+		doc["minLength"] = int64(s.minLength)
+	}
+	if s.hasMax {
+		doc["maxLength"] = int64(s.maxLength)
+	}
+	if s.pattern != nil {
+		doc["pattern"] = s.pattern.String()
+	}
+	return doc
+}
+
+// bound returns the keyword to use for a minimum or maximum.
+func bound(inclusive, exclusive string, isExclusive bool) string {
+	if isExclusive {
+		return exclusive
+	}
+	return inclusive
+}
@@ -0,0 +1,72 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/jsonschema"
+	"github.com/tvarney/maputil/schema"
+)
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+	s := schema.Object(
+		schema.Field("name", schema.String().MinLength(1).MaxLength(8).Pattern("^[a-z]+$").Required()),
+		schema.Field("mode", schema.String().Enum("a", "b").Default("a")),
+		schema.Field("port", schema.Integer().Min(1).ExclusiveMax(65536).Default(80)),
+		schema.Field("ratio", schema.Number().ExclusiveMin(0).Max(1)),
+		schema.Field("debug", schema.Boolean().Default(false)),
+		schema.Field("tags", schema.Array(schema.String()).MinItems(1).MaxItems(4)),
+		schema.Field("any", schema.Array(nil)),
+		schema.Field("extra", schema.Any()),
+		schema.Field("none", schema.Null()),
+	).AdditionalFields(false)
+
+	doc := schema.Document(s)
+	require.Equal(t, map[string]interface{}{
+		"$schema":              jsonschema.Draft,
+		"type":                 "object",
+		"required":             []interface{}{"name"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":      "string",
+				"minLength": int64(1),
+				"maxLength": int64(8),
+				"pattern":   "^[a-z]+$",
+			},
+			"mode":  map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}, "default": "a"},
+			"port":  map[string]interface{}{"type": "integer", "minimum": int64(1), "exclusiveMaximum": int64(65536), "default": int64(80)},
+			"ratio": map[string]interface{}{"type": "number", "exclusiveMinimum": 0.0, "maximum": 1.0},
+			"debug": map[string]interface{}{"type": "boolean", "default": false},
+			"tags": map[string]interface{}{
+				"type":     "array",
+				"items":    map[string]interface{}{"type": "string"},
+				"minItems": int64(1),
+				"maxItems": int64(4),
+			},
+			"any":   map[string]interface{}{"type": "array"},
+			"extra": map[string]interface{}{},
+			"none":  map[string]interface{}{"type": "null"},
+		},
+	}, doc)
+
+	// The exported document must accept and reject the same values as the
+	// schema it was generated from.
+	compiled, err := jsonschema.Compile(doc)
+	require.NoError(t, err)
+	values := []map[string]interface{}{
+		{"name": "abc", "port": 65535, "ratio": 1, "tags": []interface{}{"x"}},
+		{"name": "", "port": 65536},
+		{"name": "ABC", "mode": "c", "ratio": 0, "tags": []interface{}{}},
+		{"nmae": "abc"},
+	}
+	for _, v := range values {
+		expected := errctx.New()
+		s.Validate(expected, v)
+		actual := errctx.New()
+		compiled.Validate(actual, v)
+		require.Equal(t, expected.ErrorCount(), actual.ErrorCount(), "%v", v)
+	}
+}
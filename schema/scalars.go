@@ -0,0 +1,366 @@
+package schema
+
+import (
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+)
+
+func lengthError(n int, constraint string, limit int) maputil.OutOfRangeError {
+	return maputil.OutOfRangeError{
+		Value:      "of length " + strconv.Itoa(n),
+		Constraint: constraint,
+		Limit:      strconv.Itoa(limit),
+	}
+}
+
+// AnySchema is a schema which accepts any value.
+type AnySchema struct {
+	required bool
+}
+
+// Any returns a new schema which accepts any value.
+func Any() *AnySchema {
+	return &AnySchema{}
+}
+
+// Required marks the schema as required.
+func (s *AnySchema) Required() *AnySchema {
+	s.required = true
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *AnySchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate accepts any value.
+func (s *AnySchema) Validate(ctx *errctx.Context, v interface{}) {}
+
+// NullSchema is a schema which only accepts null.
+type NullSchema struct {
+	required bool
+}
+
+// Null returns a new schema which only accepts null.
+func Null() *NullSchema {
+	return &NullSchema{}
+}
+
+// Required marks the schema as required.
+func (s *NullSchema) Required() *NullSchema {
+	s.required = true
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *NullSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is null.
+func (s *NullSchema) Validate(ctx *errctx.Context, v interface{}) {
+	ctx.Error(maputil.Is(v, maputil.TypeNull))
+}
+
+// BooleanSchema is a schema for boolean values.
+type BooleanSchema struct {
+	required bool
+	def      interface{}
+}
+
+// Boolean returns a new boolean schema.
+func Boolean() *BooleanSchema {
+	return &BooleanSchema{}
+}
+
+// Required marks the schema as required.
+func (s *BooleanSchema) Required() *BooleanSchema {
+	s.required = true
+	return s
+}
+
+// Default sets the default value of the schema.
+//
+// The default is not used when validating; it is included in the output of
+// JSONSchema so that editors may offer it.
+func (s *BooleanSchema) Default(v bool) *BooleanSchema {
+	s.def = v
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *BooleanSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is a boolean.
+func (s *BooleanSchema) Validate(ctx *errctx.Context, v interface{}) {
+	_, err := maputil.AsBoolean(v)
+	ctx.Error(err)
+}
+
+// IntegerSchema is a schema for integer values.
+type IntegerSchema struct {
+	required bool
+	def      interface{}
+	min      int64
+	max      int64
+	hasMin   bool
+	hasMax   bool
+	exclMin  bool
+	exclMax  bool
+}
+
+// Integer returns a new integer schema.
+func Integer() *IntegerSchema {
+	return &IntegerSchema{}
+}
+
+// Required marks the schema as required.
+func (s *IntegerSchema) Required() *IntegerSchema {
+	s.required = true
+	return s
+}
+
+// Default sets the default value of the schema.
+//
+// The default is not used when validating; it is included in the output of
+// JSONSchema so that editors may offer it.
+func (s *IntegerSchema) Default(v int64) *IntegerSchema {
+	s.def = v
+	return s
+}
+
+// Min sets the inclusive minimum value.
+func (s *IntegerSchema) Min(v int64) *IntegerSchema {
+	s.min, s.hasMin, s.exclMin = v, true, false
+	return s
+}
+
+// Max sets the inclusive maximum value.
+func (s *IntegerSchema) Max(v int64) *IntegerSchema {
+	s.max, s.hasMax, s.exclMax = v, true, false
+	return s
+}
+
+// ExclusiveMin sets the exclusive minimum value.
+func (s *IntegerSchema) ExclusiveMin(v int64) *IntegerSchema {
+	s.min, s.hasMin, s.exclMin = v, true, true
+	return s
+}
+
+// ExclusiveMax sets the exclusive maximum value.
+func (s *IntegerSchema) ExclusiveMax(v int64) *IntegerSchema {
+	s.max, s.hasMax, s.exclMax = v, true, true
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *IntegerSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is an integer within the bounds of the
+// schema.
+func (s *IntegerSchema) Validate(ctx *errctx.Context, v interface{}) {
+	i, err := maputil.AsInteger(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	value := strconv.FormatInt(i, 10)
+	lower, upper := strconv.FormatInt(s.min, 10), strconv.FormatInt(s.max, 10)
+	switch {
+	case s.hasMin && s.exclMin && i <= s.min:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "exclusiveMinimum", Limit: lower})
+	case s.hasMin && i < s.min:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "minimum", Limit: lower})
+	}
+	switch {
+	case s.hasMax && s.exclMax && i >= s.max:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "exclusiveMaximum", Limit: upper})
+	case s.hasMax && i > s.max:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "maximum", Limit: upper})
+	}
+}
+
+// NumberSchema is a schema for numeric values.
+type NumberSchema struct {
+	required bool
+	def      interface{}
+	min      float64
+	max      float64
+	hasMin   bool
+	hasMax   bool
+	exclMin  bool
+	exclMax  bool
+}
+
+// Number returns a new number schema.
+func Number() *NumberSchema {
+	return &NumberSchema{}
+}
+
+// Required marks the schema as required.
+func (s *NumberSchema) Required() *NumberSchema {
+	s.required = true
+	return s
+}
+
+// Default sets the default value of the schema.
+//
+// The default is not used when validating; it is included in the output of
+// JSONSchema so that editors may offer it.
+func (s *NumberSchema) Default(v float64) *NumberSchema {
+	s.def = v
+	return s
+}
+
+// Min sets the inclusive minimum value.
+func (s *NumberSchema) Min(v float64) *NumberSchema {
+	s.min, s.hasMin, s.exclMin = v, true, false
+	return s
+}
+
+// Max sets the inclusive maximum value.
+func (s *NumberSchema) Max(v float64) *NumberSchema {
+	s.max, s.hasMax, s.exclMax = v, true, false
+	return s
+}
+
+// ExclusiveMin sets the exclusive minimum value.
+func (s *NumberSchema) ExclusiveMin(v float64) *NumberSchema {
+	s.min, s.hasMin, s.exclMin = v, true, true
+	return s
+}
+
+// ExclusiveMax sets the exclusive maximum value.
+func (s *NumberSchema) ExclusiveMax(v float64) *NumberSchema {
+	s.max, s.hasMax, s.exclMax = v, true, true
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *NumberSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is a number within the bounds of the schema.
+func (s *NumberSchema) Validate(ctx *errctx.Context, v interface{}) {
+	f, err := maputil.AsNumber(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	value := formatFloat(f)
+	switch {
+	case s.hasMin && s.exclMin && f <= s.min:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "exclusiveMinimum", Limit: formatFloat(s.min)})
+	case s.hasMin && f < s.min:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "minimum", Limit: formatFloat(s.min)})
+	}
+	switch {
+	case s.hasMax && s.exclMax && f >= s.max:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "exclusiveMaximum", Limit: formatFloat(s.max)})
+	case s.hasMax && f > s.max:
+		ctx.Error(maputil.OutOfRangeError{Value: value, Constraint: "maximum", Limit: formatFloat(s.max)})
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// StringSchema is a schema for string values.
+type StringSchema struct {
+	required  bool
+	def       interface{}
+	enum      []string
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+	hasMin    bool
+	hasMax    bool
+}
+
+// String returns a new string schema.
+func String() *StringSchema {
+	return &StringSchema{}
+}
+
+// Required marks the schema as required.
+func (s *StringSchema) Required() *StringSchema {
+	s.required = true
+	return s
+}
+
+// Default sets the default value of the schema.
+//
+// The default is not used when validating; it is included in the output of
+// JSONSchema so that editors may offer it.
+func (s *StringSchema) Default(v string) *StringSchema {
+	s.def = v
+	return s
+}
+
+// Enum restricts the value to one of the given strings.
+func (s *StringSchema) Enum(values ...string) *StringSchema {
+	s.enum = values
+	return s
+}
+
+// MinLength sets the minimum length of the string in characters.
+func (s *StringSchema) MinLength(n int) *StringSchema {
+	s.minLength, s.hasMin = n, true
+	return s
+}
+
+// MaxLength sets the maximum length of the string in characters.
+func (s *StringSchema) MaxLength(n int) *StringSchema {
+	s.maxLength, s.hasMax = n, true
+	return s
+}
+
+// Pattern requires the string to match the given regular expression.
+//
+// Pattern panics if the expression does not compile.
+func (s *StringSchema) Pattern(expr string) *StringSchema {
+	s.pattern = regexp.MustCompile(expr)
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *StringSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is a string which satisfies the constraints
+// of the schema.
+func (s *StringSchema) Validate(ctx *errctx.Context, v interface{}) {
+	str, err := maputil.AsString(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if s.enum != nil {
+		ctx.Error(maputil.CheckEnum(str, s.enum))
+	}
+	n := utf8.RuneCountInString(str)
+	if s.hasMin && n < s.minLength {
+		ctx.Error(lengthError(n, "minLength", s.minLength))
+	}
+	if s.hasMax && n > s.maxLength {
+		ctx.Error(lengthError(n, "maxLength", s.maxLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		ctx.Error(maputil.PatternError{Value: str, Pattern: s.pattern.String()})
+	}
+}
@@ -0,0 +1,143 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/schema"
+)
+
+// validate runs the schema against the value and returns the printed errors.
+func validate(s schema.Schema, v interface{}) []string {
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	s.Validate(ctx, v)
+	if sb.Len() == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+}
+
+func TestAny(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, validate(schema.Any(), nil))
+	require.Nil(t, validate(schema.Any(), []interface{}{1}))
+	require.False(t, schema.Any().IsRequired())
+	require.True(t, schema.Any().Required().IsRequired())
+}
+
+func TestNull(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, validate(schema.Null(), nil))
+	require.Equal(t, []string{": invalid type integer; expected null"}, validate(schema.Null(), 1))
+	require.True(t, schema.Null().Required().IsRequired())
+}
+
+func TestBoolean(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, validate(schema.Boolean(), true))
+	require.Equal(t, []string{": invalid type string; expected boolean"}, validate(schema.Boolean(), "true"))
+	require.True(t, schema.Boolean().Required().IsRequired())
+}
+
+func TestInteger(t *testing.T) {
+	t.Parallel()
+	t.Run("Type", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(schema.Integer(), 10))
+		require.Equal(t, []string{": invalid type number; expected integer"}, validate(schema.Integer(), 1.5))
+	})
+	t.Run("Inclusive", func(t *testing.T) {
+		t.Parallel()
+		s := schema.Integer().Min(1).Max(65535).Required()
+		require.True(t, s.IsRequired())
+		require.Nil(t, validate(s, 1))
+		require.Nil(t, validate(s, 65535))
+		require.Equal(t, []string{": invalid value 0; minimum is 1"}, validate(s, 0))
+		require.Equal(t, []string{": invalid value 65536; maximum is 65535"}, validate(s, 65536))
+	})
+	t.Run("Exclusive", func(t *testing.T) {
+		t.Parallel()
+		s := schema.Integer().ExclusiveMin(0).ExclusiveMax(10)
+		require.Nil(t, validate(s, 1))
+		require.Equal(t, []string{": invalid value 0; exclusiveMinimum is 0"}, validate(s, 0))
+		require.Equal(t, []string{": invalid value 10; exclusiveMaximum is 10"}, validate(s, 10))
+	})
+}
+
+func TestNumber(t *testing.T) {
+	t.Parallel()
+	t.Run("Type", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(schema.Number(), 1))
+		require.Nil(t, validate(schema.Number(), 1.5))
+		require.Equal(t, []string{": invalid type string; expected number"}, validate(schema.Number(), "1"))
+	})
+	t.Run("Inclusive", func(t *testing.T) {
+		t.Parallel()
+		s := schema.Number().Min(0.5).Max(1.5).Required()
+		require.True(t, s.IsRequired())
+		require.Nil(t, validate(s, 0.5))
+		require.Equal(t, []string{": invalid value 0.25; minimum is 0.5"}, validate(s, 0.25))
+		require.Equal(t, []string{": invalid value 2; maximum is 1.5"}, validate(s, 2))
+	})
+	t.Run("Exclusive", func(t *testing.T) {
+		t.Parallel()
+		s := schema.Number().ExclusiveMin(0).ExclusiveMax(1)
+		require.Nil(t, validate(s, 0.5))
+		require.Equal(t, []string{": invalid value 0; exclusiveMinimum is 0"}, validate(s, 0))
+		require.Equal(t, []string{": invalid value 1; exclusiveMaximum is 1"}, validate(s, 1))
+	})
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+	t.Run("Type", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(schema.String(), "a"))
+		require.Equal(t, []string{": invalid type integer; expected string"}, validate(schema.String(), 1))
+	})
+	t.Run("Enum", func(t *testing.T) {
+		t.Parallel()
+		s := schema.String().Enum("a", "b").Required()
+		require.True(t, s.IsRequired())
+		require.Nil(t, validate(s, "a"))
+		errs := validate(s, "c")
+		require.Equal(t, []string{`: invalid value "c"; expected "a" or "b"`}, errs)
+	})
+	t.Run("Length", func(t *testing.T) {
+		t.Parallel()
+		s := schema.String().MinLength(2).MaxLength(3)
+		require.Nil(t, validate(s, "héé"))
+		require.Equal(t, []string{": invalid value of length 1; minLength is 2"}, validate(s, "a"))
+		require.Equal(t, []string{": invalid value of length 4; maxLength is 3"}, validate(s, "abcd"))
+	})
+	t.Run("Pattern", func(t *testing.T) {
+		t.Parallel()
+		s := schema.String().Pattern("^[a-z]+$")
+		require.Nil(t, validate(s, "abc"))
+		require.Equal(t, []string{`: invalid value "ABC"; expected to match "^[a-z]+$"`}, validate(s, "ABC"))
+		require.Panics(t, func() { schema.String().Pattern("(") })
+	})
+}
+
+func TestErrorTypes(t *testing.T) {
+	t.Parallel()
+	s := schema.Object(
+		schema.Field("a", schema.Integer().Required()),
+		schema.Field("b", schema.Integer().Max(1)),
+		schema.Field("c", schema.String()),
+	)
+	ctx := errctx.New()
+	collector := &errctx.ErrorCollector{}
+	ctx.Handler = collector
+	s.Validate(ctx, map[string]interface{}{"b": 2, "c": false})
+	require.Len(t, collector.Entries, 3)
+	require.True(t, errors.Is(collector.Entries[0].Err, maputil.ErrMissingRequiredValue))
+	require.True(t, errors.Is(collector.Entries[1].Err, maputil.ErrInvalidValue))
+	require.True(t, errors.Is(collector.Entries[2].Err, maputil.ErrInvalidType))
+}
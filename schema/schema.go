@@ -0,0 +1,210 @@
+// Package schema provides a declarative builder for validating JSON-like
+// values.
+//
+// Schemas are built with chained method calls and report every problem to an
+// errctx.Context:
+//
+//	s := schema.Object(
+//		schema.Field("port", schema.Integer().Min(1).Max(65535).Required()),
+//		schema.Field("mode", schema.String().Enum("a", "b")),
+//	)
+//	s.Validate(ctx, m)
+//
+// Type mismatches are reported as maputil.InvalidTypeError, missing required
+// fields as maputil.MissingRequiredValueError, and all other failures as errors
+// which unwrap to maputil.ErrInvalidValue.
+//
+// Schemas may also be exported as JSON Schema documents with Document, which
+// lets editors check and complete the files a schema describes.
+package schema
+
+import (
+	"sort"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+	"github.com/tvarney/maputil/unpack"
+)
+
+// Schema is the interface implemented by all schema types.
+type Schema interface {
+	// Validate checks the given value, reporting any problems to the context
+	// at its current path.
+	Validate(ctx *errctx.Context, v interface{})
+
+	// IsRequired returns true if the schema was marked as required.
+	IsRequired() bool
+
+	// JSONSchema returns the schema as a JSON Schema document.
+	JSONSchema() map[string]interface{}
+}
+
+// FieldDef is a named field of an object schema.
+type FieldDef struct {
+	Name   string
+	Schema Schema
+}
+
+// Field returns a new field definition for an object schema.
+func Field(name string, s Schema) FieldDef {
+	return FieldDef{Name: name, Schema: s}
+}
+
+// ObjectSchema is a schema for objects with a known set of fields.
+type ObjectSchema struct {
+	Fields []FieldDef
+
+	required   bool
+	additional bool
+}
+
+// Object returns a new object schema with the given fields.
+//
+// Keys which are not defined as fields are allowed unless disabled with
+// AdditionalFields.
+func Object(fields ...FieldDef) *ObjectSchema {
+	return &ObjectSchema{
+		Fields:     fields,
+		additional: true,
+	}
+}
+
+// Required marks the schema as required.
+func (s *ObjectSchema) Required() *ObjectSchema {
+	s.required = true
+	return s
+}
+
+// AdditionalFields sets if keys which are not defined as fields are allowed.
+//
+// If not allowed, each such key is reported as a maputil.UnknownKeyError.
+func (s *ObjectSchema) AdditionalFields(allowed bool) *ObjectSchema {
+	s.additional = allowed
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *ObjectSchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is an object and that each field is valid.
+func (s *ObjectSchema) Validate(ctx *errctx.Context, v interface{}) {
+	m, err := maputil.AsObject(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	for _, f := range s.Fields {
+		fv, ok := m[f.Name]
+		ctx.MarkKey(m, f.Name)
+		if !ok {
+			if f.Schema.IsRequired() {
+				ctx.ErrorWithKey(maputil.MissingRequiredValueError{Key: f.Name}, f.Name)
+			}
+			continue
+		}
+		scope := ctx.Enter(mpath.Key(f.Name))
+		f.Schema.Validate(ctx, fv)
+		ctx.Leave(scope)
+	}
+
+	if !s.additional {
+		s.checkAdditional(ctx, m)
+	}
+}
+
+func (s *ObjectSchema) checkAdditional(ctx *errctx.Context, m map[string]interface{}) {
+	known := make([]string, 0, len(s.Fields))
+	defined := make(map[string]struct{}, len(s.Fields))
+	for _, f := range s.Fields {
+		known = append(known, f.Name)
+		defined[f.Name] = struct{}{}
+	}
+
+	var unknown []string
+	for k := range m {
+		if _, ok := defined[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	for _, k := range unknown {
+		ctx.ErrorWithKey(maputil.UnknownKeyError{
+			Key:        k,
+			Suggestion: unpack.SuggestKey(k, known),
+		}, k)
+	}
+}
+
+// ArraySchema is a schema for arrays.
+type ArraySchema struct {
+	Items Schema
+
+	required bool
+	minItems int
+	maxItems int
+	hasMin   bool
+	hasMax   bool
+}
+
+// Array returns a new array schema whose elements must match the given
+// schema.
+//
+// If items is nil, elements are not checked.
+func Array(items Schema) *ArraySchema {
+	return &ArraySchema{Items: items}
+}
+
+// Required marks the schema as required.
+func (s *ArraySchema) Required() *ArraySchema {
+	s.required = true
+	return s
+}
+
+// MinItems sets the minimum number of elements.
+func (s *ArraySchema) MinItems(n int) *ArraySchema {
+	s.minItems, s.hasMin = n, true
+	return s
+}
+
+// MaxItems sets the maximum number of elements.
+func (s *ArraySchema) MaxItems(n int) *ArraySchema {
+	s.maxItems, s.hasMax = n, true
+	return s
+}
+
+// IsRequired returns true if the schema was marked as required.
+func (s *ArraySchema) IsRequired() bool {
+	return s.required
+}
+
+// Validate checks that the value is an array of a valid length, and that each
+// element is valid.
+func (s *ArraySchema) Validate(ctx *errctx.Context, v interface{}) {
+	a, err := maputil.AsArray(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if s.hasMin && len(a) < s.minItems {
+		ctx.Error(lengthError(len(a), "minItems", s.minItems))
+	}
+	if s.hasMax && len(a) > s.maxItems {
+		ctx.Error(lengthError(len(a), "maxItems", s.maxItems))
+	}
+	if s.Items == nil {
+		return
+	}
+	for i, e := range a {
+		if ctx.Canceled() {
+			return
+		}
+		scope := ctx.Enter(mpath.Index(i))
+		s.Items.Validate(ctx, e)
+		ctx.Leave(scope)
+	}
+}
@@ -0,0 +1,100 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/schema"
+)
+
+func TestObject(t *testing.T) {
+	t.Parallel()
+	server := schema.Object(
+		schema.Field("port", schema.Integer().Min(1).Max(65535).Required()),
+		schema.Field("mode", schema.String().Enum("a", "b", "c")),
+		schema.Field("tags", schema.Array(schema.String()).MaxItems(2)),
+	)
+	s := schema.Object(
+		schema.Field("name", schema.String().Required()),
+		schema.Field("server", server.Required()),
+		schema.Field("servers", schema.Array(server)),
+	)
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(s, map[string]interface{}{
+			"name":   "n",
+			"server": map[string]interface{}{"port": 80, "mode": "a"},
+			"extra":  true,
+		}))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		errs := validate(s, map[string]interface{}{
+			"server": map[string]interface{}{"port": 0, "mode": "d"},
+			"servers": []interface{}{
+				map[string]interface{}{"tags": []interface{}{"a", 1, "c"}},
+				"bad",
+			},
+		})
+		require.Equal(t, []string{
+			`name: missing required value "name"`,
+			`server.port: invalid value 0; minimum is 1`,
+			`server.mode: invalid value "d"; expected one of "a", "b", or "c"`,
+			`servers[0].port: missing required value "port"`,
+			`servers[0].tags: invalid value of length 3; maxItems is 2`,
+			`servers[0].tags[1]: invalid type integer; expected string`,
+			`servers[1]: invalid type string; expected object`,
+		}, errs)
+	})
+	t.Run("NotObject", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, []string{": invalid type array; expected object"}, validate(s, []interface{}{}))
+	})
+	t.Run("AdditionalFields", func(t *testing.T) {
+		t.Parallel()
+		strict := schema.Object(
+			schema.Field("timeout", schema.Integer()),
+		).AdditionalFields(false)
+		require.Equal(t, []string{
+			`timout: unknown key "timout"; did you mean "timeout"?`,
+			`zzz: unknown key "zzz"`,
+		}, validate(strict, map[string]interface{}{"timout": 1, "zzz": 2}))
+	})
+	t.Run("KeyTracking", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"name": "n", "server": map[string]interface{}{"port": 1}}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Keys = errctx.NewKeyTracker()
+		s.Validate(ctx, m)
+		require.Equal(t, []string{"name", "server", "servers"}, ctx.Keys.Known(m))
+	})
+	t.Run("Consume", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"name": "n", "server": map[string]interface{}{"port": 1}}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Consume = true
+		s.Validate(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, map[string]interface{}{"name": "n", "server": map[string]interface{}{"port": 1}}, m)
+	})
+}
+
+func TestArray(t *testing.T) {
+	t.Parallel()
+	t.Run("NoItems", func(t *testing.T) {
+		t.Parallel()
+		require.Nil(t, validate(schema.Array(nil), []interface{}{1, "a"}))
+	})
+	t.Run("Length", func(t *testing.T) {
+		t.Parallel()
+		s := schema.Array(nil).MinItems(1).MaxItems(2).Required()
+		require.True(t, s.IsRequired())
+		require.Equal(t, []string{": invalid value of length 0; minItems is 1"}, validate(s, []interface{}{}))
+		require.Equal(t, []string{": invalid value of length 3; maxItems is 2"}, validate(s, []interface{}{1, 2, 3}))
+	})
+	t.Run("NotArray", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, []string{": invalid type object; expected array"}, validate(schema.Array(nil), map[string]interface{}{}))
+	})
+}
@@ -0,0 +1,121 @@
+package maputil
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AsDuration attempts to coerce the value into a duration.
+//
+// Strings are parsed with time.ParseDuration, so must be in a form such as
+// "300ms" or "1h30m". If unit is greater than zero numbers are also accepted
+// and are interpreted as a count of the unit, so that a unit of time.Second
+// converts 30 to 30 seconds and 1.5 to 1500 milliseconds. If unit is zero
+// numbers are rejected.
+func AsDuration(v interface{}, unit time.Duration) (time.Duration, error) {
+	if s, ok := v.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, InvalidValueError{
+				Value:    strconv.Quote(s),
+				Expected: `duration such as "1m30s"`,
+			}
+		}
+		return d, nil
+	}
+
+	if unit > 0 {
+		switch TypeName(v) {
+		case TypeInteger:
+			i, err := asSigned(v, 64, "time.Duration")
+			if err != nil {
+				return 0, err
+			}
+			if i > math.MaxInt64/int64(unit) || i < math.MinInt64/int64(unit) {
+				return 0, OverflowError{Value: strconv.FormatInt(i, 10), Type: "time.Duration"}
+			}
+			return time.Duration(i) * unit, nil
+		case TypeNumber:
+			f, err := AsNumber(v)
+			if err != nil {
+				return 0, err
+			}
+			d, ok := floatToInt64(f * float64(unit))
+			if !ok {
+				return 0, OverflowError{Value: formatNumber(f), Type: "time.Duration"}
+			}
+			return time.Duration(d), nil
+		}
+		return 0, InvalidTypeError{
+			Expected: []string{TypeString, TypeNumber},
+			Actual:   TypeName(v),
+		}
+	}
+	return 0, InvalidTypeError{
+		Expected: []string{TypeString},
+		Actual:   TypeName(v),
+	}
+}
+
+// AsTime attempts to coerce the value into a time.
+//
+// Strings are parsed using each of the layouts in turn, as with time.Parse,
+// returning the first successful result. If no layouts are given, the string
+// must be in RFC 3339 format, optionally with fractional seconds. Numbers are
+// interpreted as seconds since the Unix epoch, and the resulting time is in
+// UTC.
+func AsTime(v interface{}, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+
+	switch TypeName(v) {
+	case TypeString:
+		s := v.(string)
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		quoted := make([]string, len(layouts))
+		for i, layout := range layouts {
+			quoted[i] = strconv.Quote(layout)
+		}
+		return time.Time{}, InvalidValueError{
+			Value:    strconv.Quote(s),
+			Expected: "time in format " + strings.Join(quoted, " or "),
+		}
+	case TypeInteger:
+		i, err := asSigned(v, 64, "time.Time")
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(i, 0).UTC(), nil
+	case TypeNumber:
+		f, err := AsNumber(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		sec := math.Floor(f)
+		s, ok := floatToInt64(sec)
+		if !ok {
+			return time.Time{}, OverflowError{Value: formatNumber(f), Type: "time.Time"}
+		}
+		return time.Unix(s, int64(math.Round((f-sec)*1e9))).UTC(), nil
+	}
+	return time.Time{}, InvalidTypeError{
+		Expected: []string{TypeString, TypeNumber},
+		Actual:   TypeName(v),
+	}
+}
+
+// floatToInt64 truncates the float to an integer, returning false if it is
+// NaN or outside of the range of an int64.
+func floatToInt64(f float64) (int64, bool) {
+	if math.IsNaN(f) || f < -(1<<63) || f >= 1<<63 {
+		return 0, false
+	}
+	return int64(f), true
+}
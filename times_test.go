@@ -0,0 +1,138 @@
+package maputil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+)
+
+func TestAsDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		data     interface{}
+		unit     time.Duration
+		expected time.Duration
+	}{
+		{"String", "1m30s", 0, 90 * time.Second},
+		{"StringWithUnit", "250ms", time.Second, 250 * time.Millisecond},
+		{"Seconds", 30, time.Second, 30 * time.Second},
+		{"Milliseconds", int64(1500), time.Millisecond, 1500 * time.Millisecond},
+		{"Fraction", 1.5, time.Second, 1500 * time.Millisecond},
+		{"Number", json.Number("2"), time.Minute, 2 * time.Minute},
+		{"Negative", -1, time.Hour, -time.Hour},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := maputil.AsDuration(tc.data, tc.unit)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, d)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := maputil.AsDuration("30 seconds", time.Second)
+		require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+		require.EqualError(t, err, `invalid value "30 seconds"; expected duration such as "1m30s"`)
+
+		_, err = maputil.AsDuration(30, 0)
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeString},
+			Actual:   maputil.TypeInteger,
+		}.Error())
+		_, err = maputil.AsDuration(true, time.Second)
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeString, maputil.TypeNumber},
+			Actual:   maputil.TypeBoolean,
+		}.Error())
+
+		_, err = maputil.AsDuration(int64(math.MaxInt64/1000), time.Second)
+		require.EqualError(t, err, "invalid value 9223372036854775; overflows time.Duration")
+		_, err = maputil.AsDuration(1e300, time.Nanosecond)
+		require.EqualError(t, err, "invalid value 1e+300; overflows time.Duration")
+	})
+}
+
+func TestAsTime(t *testing.T) {
+	t.Parallel()
+	expected := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		data     interface{}
+		layouts  []string
+		expected time.Time
+	}{
+		{"RFC3339", "2026-01-02T15:04:05Z", nil, expected},
+		{"Fraction", "2026-01-02T15:04:05.5Z", nil, expected.Add(500 * time.Millisecond)},
+		{"Layouts", "2026-01-02", []string{time.RFC3339, "2006-01-02"}, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Epoch", expected.Unix(), nil, expected},
+		{"EpochFraction", float64(expected.Unix()) + 0.25, nil, expected.Add(250 * time.Millisecond)},
+		{"EpochNumber", json.Number("1767366245"), nil, expected},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := maputil.AsTime(tc.data, tc.layouts...)
+			require.NoError(t, err)
+			require.True(t, tc.expected.Equal(v), "expected %s, got %s", tc.expected, v)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := maputil.AsTime("yesterday")
+		require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+		require.EqualError(t, err, `invalid value "yesterday"; expected time in format "2006-01-02T15:04:05Z07:00"`)
+		_, err = maputil.AsTime("2026-01-02T15:04:05Z", "2006-01-02", "15:04")
+		require.EqualError(t, err, `invalid value "2026-01-02T15:04:05Z"; expected time in format "2006-01-02" or "15:04"`)
+		_, err = maputil.AsTime(nil)
+		require.EqualError(t, err, maputil.InvalidTypeError{
+			Expected: []string{maputil.TypeString, maputil.TypeNumber},
+			Actual:   maputil.TypeNull,
+		}.Error())
+	})
+}
+
+func TestTimeAccessors(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{keyGood: "10s", keyBad: testString, keyBadVal: 5}
+
+	d, err := maputil.RequireDuration(m, keyGood, 0)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, d)
+	_, err = maputil.RequireDuration(m, keyMissing, 0)
+	require.EqualError(t, err, maputil.MissingRequiredValueError{Key: keyMissing}.Error())
+	d, err = maputil.OptionalDuration(m, keyBad, 0, time.Minute)
+	require.Error(t, err)
+	require.Equal(t, time.Minute, d)
+	d, ok, err := maputil.GetDuration(m, keyBadVal, time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 5*time.Millisecond, d)
+
+	dv := time.Unix(0, 0)
+	v, err := maputil.OptionalTime(m, keyMissing, dv)
+	require.NoError(t, err)
+	require.Equal(t, dv, v)
+	v, err = maputil.RequireTime(m, keyBadVal)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), v.Unix())
+
+	c := maputil.Copy(m)
+	_, ok, err = maputil.PopTime(c, keyBad)
+	require.True(t, ok)
+	require.Error(t, err)
+	require.NotContains(t, c, keyBad)
+	_, ok, err = maputil.PopDuration(c, keyBad, 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
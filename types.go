@@ -3,6 +3,8 @@ package maputil
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"strconv"
 )
 
 // Type name constants encode the JSON-like names of the types handled by the
@@ -64,6 +66,10 @@ func TypeName(v interface{}) string {
 		if err == nil {
 			return TypeInteger
 		}
+		// Integers too large for an int64 are still integers.
+		if _, ok := new(big.Int).SetString(d.String(), 10); ok {
+			return TypeInteger
+		}
 		return TypeNumber
 	case map[string]interface{}:
 		return TypeObject
@@ -119,6 +125,10 @@ func AsBoolean(v interface{}) (bool, error) {
 
 func truncFloat(v float64) (int64, error) {
 	if math.Trunc(v) == v {
+		// The bounds are exact powers of two, so the comparison is exact.
+		if v < -(1<<63) || v >= 1<<63 {
+			return 0, OverflowError{Value: strconv.FormatFloat(v, 'g', -1, 64), Type: "int64"}
+		}
 		return int64(v), nil
 	}
 	return 0, InvalidTypeError{
@@ -127,7 +137,17 @@ func truncFloat(v float64) (int64, error) {
 	}
 }
 
+func uintToInt(v uint64) (int64, error) {
+	if v > math.MaxInt64 {
+		return 0, OverflowError{Value: strconv.FormatUint(v, 10), Type: "int64"}
+	}
+	return int64(v), nil
+}
+
 // AsInteger attempts to coerce the value into an integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int64.
 func AsInteger(v interface{}) (int64, error) {
 	switch d := v.(type) {
 	case int:
@@ -141,7 +161,7 @@ func AsInteger(v interface{}) (int64, error) {
 	case int64:
 		return d, nil
 	case uint:
-		return int64(d), nil
+		return uintToInt(uint64(d))
 	case uint8:
 		return int64(d), nil
 	case uint16:
@@ -149,7 +169,7 @@ func AsInteger(v interface{}) (int64, error) {
 	case uint32:
 		return int64(d), nil
 	case uint64:
-		return int64(d), nil
+		return uintToInt(d)
 	case float32:
 		return truncFloat(float64(d))
 	case float64:
@@ -159,7 +179,11 @@ func AsInteger(v interface{}) (int64, error) {
 			return i, nil
 		}
 		f, _ := d.Float64()
-		return truncFloat(f)
+		i, err := truncFloat(f)
+		if _, ok := err.(OverflowError); ok {
+			return 0, OverflowError{Value: d.String(), Type: "int64"}
+		}
+		return i, err
 	}
 	return 0, InvalidTypeError{
 		Expected: []string{TypeInteger},
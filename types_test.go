@@ -2,6 +2,8 @@ package maputil_test
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -355,6 +357,40 @@ func TestAsInteger(t *testing.T) {
 		}.Error())
 		require.Zero(t, i)
 	})
+	t.Run("Overflow", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name  string
+			data  interface{}
+			value string
+		}{
+			{"Uint64", uint64(math.MaxUint64), "18446744073709551615"},
+			{"Uint", uint(math.MaxInt64 + 1), "9223372036854775808"},
+			{"Float64", 1e300, "1e+300"},
+			{"Float64Min", -1e19, "-1e+19"},
+			{"Float32", float32(1e20), "1.0000000200408773e+20"},
+			{"GenericNumber", json.Number("18446744073709551616"), "18446744073709551616"},
+		}
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				i, err := maputil.AsInteger(tc.data)
+				require.EqualError(t, err, maputil.OverflowError{Value: tc.value, Type: "int64"}.Error())
+				require.True(t, errors.Is(err, maputil.ErrInvalidValue))
+				require.Zero(t, i)
+			})
+		}
+	})
+	t.Run("Limits", func(t *testing.T) {
+		t.Parallel()
+		i, err := maputil.AsInteger(uint64(math.MaxInt64))
+		require.NoError(t, err)
+		require.Equal(t, int64(math.MaxInt64), i)
+		i, err = maputil.AsInteger(float64(math.MinInt64))
+		require.NoError(t, err)
+		require.Equal(t, int64(math.MinInt64), i)
+	})
 }
 
 func TestAsNumber(t *testing.T) {
@@ -0,0 +1,230 @@
+package unpack
+
+import (
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// eachElement calls fn for each element of the array, with the context path
+// positioned under the key of the array, sending any errors returned by fn to
+// the context at the index of the element.
+//
+// The returned mask records which elements fn succeeded for. Elements after
+// the context is canceled are not visited and are marked as invalid.
+func eachElement(ctx *errctx.Context, key string, a []interface{}, fn func(i int, v interface{}) error) []bool {
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	return eachIndex(ctx, a, fn)
+}
+
+// eachIndex is eachElement for an array whose scope has already been entered.
+func eachIndex(ctx *errctx.Context, a []interface{}, fn func(i int, v interface{}) error) []bool {
+	valid := make([]bool, len(a))
+	for i, v := range a {
+		if ctx.Canceled() {
+			break
+		}
+		if err := fn(i, v); err != nil {
+			ctx.ErrorWithIndex(err, i)
+			continue
+		}
+		valid[i] = true
+	}
+	return valid
+}
+
+func booleanArray(ctx *errctx.Context, key string, a []interface{}) []bool {
+	if len(a) == 0 {
+		return nil
+	}
+	ba := make([]bool, 0, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsBoolean(ctx, e)
+		if err == nil {
+			ba = append(ba, v)
+		}
+		return err
+	})
+	return ba
+}
+
+func integerArray(ctx *errctx.Context, key string, a []interface{}) []int64 {
+	if len(a) == 0 {
+		return nil
+	}
+	ia := make([]int64, 0, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsInteger(ctx, e)
+		if err == nil {
+			ia = append(ia, v)
+		}
+		return err
+	})
+	return ia
+}
+
+func numberArray(ctx *errctx.Context, key string, a []interface{}) []float64 {
+	if len(a) == 0 {
+		return nil
+	}
+	na := make([]float64, 0, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsNumber(ctx, e)
+		if err == nil {
+			na = append(na, v)
+		}
+		return err
+	})
+	return na
+}
+
+func objectArray(ctx *errctx.Context, key string, a []interface{}) []map[string]interface{} {
+	if len(a) == 0 {
+		return nil
+	}
+	oa := make([]map[string]interface{}, 0, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := maputil.AsObject(e)
+		if err == nil {
+			oa = append(oa, v)
+		}
+		return err
+	})
+	return oa
+}
+
+func stringArray(ctx *errctx.Context, key string, a []interface{}) []string {
+	if len(a) == 0 {
+		return nil
+	}
+	sa := make([]string, 0, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := maputil.AsString(e)
+		if err == nil {
+			sa = append(sa, v)
+		}
+		return err
+	})
+	return sa
+}
+
+func objectArrayFunc(ctx *errctx.Context, key string, a []interface{}, fn ObjectFunc) {
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		o, err := maputil.AsObject(e)
+		if err != nil {
+			return err
+		}
+		defer ctx.Leave(ctx.Enter(mpath.Index(i)))
+		fn(ctx, o)
+		return nil
+	})
+}
+
+func booleanArrayAligned(ctx *errctx.Context, key string, a []interface{}) ([]bool, []bool) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	ba := make([]bool, len(a))
+	valid := eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsBoolean(ctx, e)
+		ba[i] = v
+		return err
+	})
+	return ba, valid
+}
+
+func integerArrayAligned(ctx *errctx.Context, key string, a []interface{}) ([]int64, []bool) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	ia := make([]int64, len(a))
+	valid := eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsInteger(ctx, e)
+		ia[i] = v
+		return err
+	})
+	return ia, valid
+}
+
+func numberArrayAligned(ctx *errctx.Context, key string, a []interface{}) ([]float64, []bool) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	na := make([]float64, len(a))
+	valid := eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := AsNumber(ctx, e)
+		na[i] = v
+		return err
+	})
+	return na, valid
+}
+
+func objectArrayAligned(ctx *errctx.Context, key string, a []interface{}) ([]map[string]interface{}, []bool) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	oa := make([]map[string]interface{}, len(a))
+	valid := eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := maputil.AsObject(e)
+		oa[i] = v
+		return err
+	})
+	return oa, valid
+}
+
+func stringArrayAligned(ctx *errctx.Context, key string, a []interface{}) ([]string, []bool) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	sa := make([]string, len(a))
+	valid := eachElement(ctx, key, a, func(i int, e interface{}) error {
+		v, err := maputil.AsString(e)
+		sa[i] = v
+		return err
+	})
+	return sa, valid
+}
+
+func integerMatrix(ctx *errctx.Context, key string, a []interface{}) [][]int64 {
+	if len(a) == 0 {
+		return nil
+	}
+	rows := make([][]int64, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		row, err := maputil.AsArray(e)
+		if err != nil {
+			return err
+		}
+		defer ctx.Leave(ctx.Enter(mpath.Index(i)))
+		rows[i] = make([]int64, len(row))
+		eachIndex(ctx, row, func(j int, e interface{}) error {
+			v, err := AsInteger(ctx, e)
+			rows[i][j] = v
+			return err
+		})
+		return nil
+	})
+	return rows
+}
+
+func stringMatrix(ctx *errctx.Context, key string, a []interface{}) [][]string {
+	if len(a) == 0 {
+		return nil
+	}
+	rows := make([][]string, len(a))
+	eachElement(ctx, key, a, func(i int, e interface{}) error {
+		row, err := maputil.AsArray(e)
+		if err != nil {
+			return err
+		}
+		defer ctx.Leave(ctx.Enter(mpath.Index(i)))
+		rows[i] = make([]string, len(row))
+		eachIndex(ctx, row, func(j int, e interface{}) error {
+			v, err := maputil.AsString(e)
+			rows[i][j] = v
+			return err
+		})
+		return nil
+	})
+	return rows
+}
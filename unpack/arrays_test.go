@@ -0,0 +1,136 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestAlignedArrays(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"ports":  []interface{}{80, "http", 443},
+		"names":  []interface{}{"a", 1, "c"},
+		"flags":  []interface{}{true, false},
+		"ratios": []interface{}{0.5, nil},
+		"items":  []interface{}{map[string]interface{}{}, "x"},
+		"empty":  []interface{}{},
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+
+	ports, valid := unpack.RequireIntegerArrayAligned(ctx, m, "ports")
+	require.Equal(t, []int64{80, 0, 443}, ports)
+	require.Equal(t, []bool{true, false, true}, valid)
+
+	names, valid := unpack.OptionalStringArrayAligned(ctx, m, "names")
+	require.Equal(t, []string{"a", "", "c"}, names)
+	require.Equal(t, []bool{true, false, true}, valid)
+
+	flags, valid := unpack.RequireBooleanArrayAligned(ctx, m, "flags")
+	require.Equal(t, []bool{true, false}, flags)
+	require.Equal(t, []bool{true, true}, valid)
+
+	ratios, valid := unpack.OptionalNumberArrayAligned(ctx, m, "ratios")
+	require.Equal(t, []float64{0.5, 0}, ratios)
+	require.Equal(t, []bool{true, false}, valid)
+
+	items, valid := unpack.RequireObjectArrayAligned(ctx, m, "items")
+	require.Equal(t, []map[string]interface{}{{}, nil}, items)
+	require.Equal(t, []bool{true, false}, valid)
+
+	ports, valid = unpack.RequireIntegerArrayAligned(ctx, m, "empty")
+	require.Nil(t, ports)
+	require.Nil(t, valid)
+	names, valid = unpack.OptionalStringArrayAligned(ctx, m, "missing")
+	require.Nil(t, names)
+	require.Nil(t, valid)
+
+	require.Equal(t, []string{
+		`ports[1]: invalid type string; expected integer`,
+		`names[1]: invalid type integer; expected string`,
+		`ratios[1]: invalid type null; expected number`,
+		`items[1]: invalid type string; expected object`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
+
+func TestMatrices(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"matrix": []interface{}{
+			[]interface{}{1, 2},
+			[]interface{}{3, 4, 5},
+			[]interface{}{6, 7, 8, "nine"},
+			"row",
+		},
+		"grid": []interface{}{
+			[]interface{}{"a", "b"},
+			[]interface{}{true},
+		},
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, [][]int64{{1, 2}, {3, 4, 5}, {6, 7, 8, 0}, nil}, unpack.RequireIntegerMatrix(ctx, m, "matrix"))
+	require.Equal(t, [][]string{{"a", "b"}, {""}}, unpack.OptionalStringMatrix(ctx, m, "grid"))
+	require.Nil(t, unpack.OptionalIntegerMatrix(ctx, m, "missing"))
+	require.Nil(t, unpack.RequireStringMatrix(ctx, m, "missing"))
+	require.Equal(t, []string{
+		`matrix[2][3]: invalid type string; expected integer`,
+		`matrix[3]: invalid type string; expected array`,
+		`grid[1][0]: invalid type boolean; expected string`,
+		`missing: missing required value "missing"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
+
+func TestDefaultArrays(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"ports": []interface{}{80, "http", 443},
+		"hosts": "localhost",
+		"empty": []interface{}{},
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, []int64{80, 443}, unpack.OptionalIntegerArrayDefault(ctx, m, "ports", []int64{8080}))
+	require.Equal(t, []string{"a"}, unpack.OptionalStringArrayDefault(ctx, m, "hosts", []string{"a"}))
+	require.Equal(t, []float64{0.5}, unpack.OptionalNumberArrayDefault(ctx, m, "missing", []float64{0.5}))
+	require.Equal(t, []bool{true}, unpack.OptionalBooleanArrayDefault(ctx, m, "missing", []bool{true}))
+	require.Nil(t, unpack.OptionalObjectArrayDefault(ctx, m, "empty", []map[string]interface{}{{}}))
+	require.Equal(t, []string{
+		`ports[1]: invalid type string; expected integer`,
+		`hosts: invalid type string; expected array`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
+
+func TestObjectArrayFunc(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a", "port": 80},
+			"b:80",
+			map[string]interface{}{"host": "c", "port": "http"},
+		},
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	var hosts []string
+	fn := func(ctx *errctx.Context, o map[string]interface{}) {
+		hosts = append(hosts, unpack.RequireString(ctx, o, "host"))
+		unpack.RequireInteger(ctx, o, "port")
+	}
+	unpack.RequireObjectArrayFunc(ctx, m, "servers", fn)
+	require.Equal(t, []string{"a", "c"}, hosts)
+	require.Len(t, ctx.Path.Elements, 0)
+
+	require.False(t, unpack.OptionalObjectArrayFunc(ctx, m, "missing", fn))
+	require.True(t, unpack.OptionalObjectArrayFunc(ctx, map[string]interface{}{"servers": []interface{}{}}, "servers", fn))
+	unpack.RequireObjectArrayFunc(ctx, m, "missing", fn)
+	require.Equal(t, []string{
+		`servers[1]: invalid type string; expected object`,
+		`servers[2].port: invalid type string; expected integer`,
+		`missing: missing required value "missing"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
@@ -0,0 +1,37 @@
+package unpack_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestBigNumbers(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"id":    json.Number("123456789012345678901234567890"),
+		"ratio": json.Number("0.25"),
+		"count": 1.5,
+		"big":   "123456789012345678901234567890",
+		"bad":   "12abc",
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, "123456789012345678901234567890", unpack.RequireBigInt(ctx, m, "id").String())
+	require.Equal(t, "0.25", unpack.RequireBigFloat(ctx, m, "ratio").String())
+	require.Equal(t, int64(3), unpack.OptionalBigInt(ctx, m, "count", big.NewInt(3)).Int64())
+	require.Equal(t, "2", unpack.OptionalBigFloat(ctx, m, "missing", big.NewFloat(2)).String())
+	require.Nil(t, unpack.RequireBigFloat(ctx, m, "missing"))
+	require.Equal(t, "123456789012345678901234567890", unpack.RequireBigInt(ctx, m, "big").String())
+	require.Nil(t, unpack.RequireBigInt(ctx, m, "bad"))
+	require.Equal(t, []string{
+		`count: invalid type number; expected integer`,
+		`missing: missing required value "missing"`,
+		`bad: invalid value "12abc"; expected decimal integer`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
@@ -0,0 +1,46 @@
+package unpack_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestConstraints(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile("^[a-z]+$")
+	m := map[string]interface{}{
+		"port":  70000,
+		"ratio": 0.5,
+		"name":  "Name",
+		"tags":  []interface{}{},
+	}
+
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, int64(70000), unpack.RequireIntegerRange(ctx, m, "port", maputil.IntegerBetween(1, 65535)))
+	require.Equal(t, 0.5, unpack.RequireNumberRange(ctx, m, "ratio", maputil.NumberBetween(0, 1)))
+	require.Equal(t, "Name", unpack.RequireStringLength(ctx, m, "name", maputil.LengthAtMost(8)))
+	require.Equal(t, "Name", unpack.RequireStringPattern(ctx, m, "name", re))
+	require.Empty(t, unpack.RequireArrayLength(ctx, m, "tags", maputil.LengthAtLeast(1)))
+	require.Equal(t, int64(80), unpack.OptionalIntegerRange(ctx, m, "port", maputil.IntegerAtMost(1024), 80))
+	require.Equal(t, 1.0, unpack.OptionalNumberRange(ctx, m, "ratio", maputil.NumberAtLeast(0.5).ExclusiveMin(), 1))
+	require.Equal(t, "x", unpack.OptionalStringLength(ctx, m, "name", maputil.LengthAtLeast(5), "x"))
+	require.Equal(t, "y", unpack.OptionalStringPattern(ctx, m, "missing", re, "y"))
+	require.Nil(t, unpack.OptionalArrayLength(ctx, m, "tags", maputil.LengthAtLeast(1), nil))
+	require.Equal(t, []string{
+		`port: invalid value 70000; maximum is 65535`,
+		`name: invalid value "Name"; expected to match "^[a-z]+$"`,
+		`tags: invalid value of length 0; minimum length is 1`,
+		`port: invalid value 70000; maximum is 1024`,
+		`ratio: invalid value 0.5; exclusive minimum is 0.5`,
+		`name: invalid value of length 4; minimum length is 5`,
+		`tags: invalid value of length 0; minimum length is 1`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	require.Equal(t, 7, ctx.ErrorCount())
+}
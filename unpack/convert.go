@@ -0,0 +1,169 @@
+package unpack
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+)
+
+// The As functions convert a single value, such as an element of an array,
+// parsing strings if the context is lenient. Unlike the rest of this package
+// they return any error rather than sending it to the context, so that the
+// caller may report it at the path of the value.
+
+// AsBoolean attempts to coerce the value into a boolean.
+func AsBoolean(ctx *errctx.Context, v interface{}) (bool, error) {
+	v, err := lenient(ctx, v, parseBoolean)
+	if err != nil {
+		return false, err
+	}
+	return maputil.AsBoolean(v)
+}
+
+// AsInteger attempts to coerce the value into an integer.
+func AsInteger(ctx *errctx.Context, v interface{}) (int64, error) {
+	v, err := lenient(ctx, v, parseInteger)
+	if err != nil {
+		return 0, err
+	}
+	return maputil.AsInteger(v)
+}
+
+// AsNumber attempts to coerce the value into a number.
+func AsNumber(ctx *errctx.Context, v interface{}) (float64, error) {
+	v, err := lenient(ctx, v, parseNumber)
+	if err != nil {
+		return 0, err
+	}
+	return maputil.AsNumber(v)
+}
+
+// AsInt attempts to coerce the value into an int.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int.
+func AsInt(ctx *errctx.Context, v interface{}) (int, error) {
+	i, err := AsInteger(ctx, v)
+	if err != nil {
+		return 0, overflowType(err, "int")
+	}
+	if int64(int(i)) != i {
+		return 0, maputil.OverflowError{Value: strconv.FormatInt(i, 10), Type: "int"}
+	}
+	return int(i), nil
+}
+
+// AsInt8 attempts to coerce the value into an 8-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int8.
+func AsInt8(ctx *errctx.Context, v interface{}) (int8, error) {
+	v, err := lenient(ctx, v, parseInteger)
+	if err != nil {
+		return 0, overflowType(err, "int8")
+	}
+	return maputil.AsInt8(v)
+}
+
+// AsInt16 attempts to coerce the value into a 16-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int16.
+func AsInt16(ctx *errctx.Context, v interface{}) (int16, error) {
+	v, err := lenient(ctx, v, parseInteger)
+	if err != nil {
+		return 0, overflowType(err, "int16")
+	}
+	return maputil.AsInt16(v)
+}
+
+// AsInt32 attempts to coerce the value into a 32-bit integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in an int32.
+func AsInt32(ctx *errctx.Context, v interface{}) (int32, error) {
+	v, err := lenient(ctx, v, parseInteger)
+	if err != nil {
+		return 0, overflowType(err, "int32")
+	}
+	return maputil.AsInt32(v)
+}
+
+// AsUint attempts to coerce the value into a uint.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint.
+func AsUint(ctx *errctx.Context, v interface{}) (uint, error) {
+	u, err := AsUint64(ctx, v)
+	if err != nil {
+		return 0, overflowType(err, "uint")
+	}
+	if uint64(uint(u)) != u {
+		return 0, maputil.OverflowError{Value: strconv.FormatUint(u, 10), Type: "uint"}
+	}
+	return uint(u), nil
+}
+
+// AsUint8 attempts to coerce the value into an 8-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint8.
+func AsUint8(ctx *errctx.Context, v interface{}) (uint8, error) {
+	v, err := lenient(ctx, v, parseUnsigned)
+	if err != nil {
+		return 0, overflowType(err, "uint8")
+	}
+	return maputil.AsUint8(v)
+}
+
+// AsUint16 attempts to coerce the value into a 16-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint16.
+func AsUint16(ctx *errctx.Context, v interface{}) (uint16, error) {
+	v, err := lenient(ctx, v, parseUnsigned)
+	if err != nil {
+		return 0, overflowType(err, "uint16")
+	}
+	return maputil.AsUint16(v)
+}
+
+// AsUint32 attempts to coerce the value into a 32-bit unsigned integer.
+//
+// An OverflowError is returned if the value is an integer which does not fit
+// in a uint32.
+func AsUint32(ctx *errctx.Context, v interface{}) (uint32, error) {
+	v, err := lenient(ctx, v, parseUnsigned)
+	if err != nil {
+		return 0, overflowType(err, "uint32")
+	}
+	return maputil.AsUint32(v)
+}
+
+// AsUint64 attempts to coerce the value into a 64-bit unsigned integer.
+//
+// An OverflowError is returned if the value is negative or too large.
+func AsUint64(ctx *errctx.Context, v interface{}) (uint64, error) {
+	v, err := lenient(ctx, v, parseUnsigned)
+	if err != nil {
+		return 0, overflowType(err, "uint64")
+	}
+	return maputil.AsUint64(v)
+}
+
+// AsFloat32 attempts to coerce the value into a 32-bit number.
+//
+// An OverflowError is returned if the value is a finite number too large in
+// magnitude for a float32.
+func AsFloat32(ctx *errctx.Context, v interface{}) (float32, error) {
+	f, err := AsNumber(ctx, v)
+	if err != nil {
+		return 0, err
+	}
+	if !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, maputil.OverflowError{Value: strconv.FormatFloat(f, 'g', -1, 64), Type: "float32"}
+	}
+	return float32(f), nil
+}
@@ -0,0 +1,66 @@
+package unpack_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestAs(t *testing.T) {
+	t.Parallel()
+	t.Run("Strict", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		i, err := unpack.AsInt(ctx, 42)
+		require.NoError(t, err)
+		require.Equal(t, 42, i)
+		u8, err := unpack.AsUint8(ctx, 255)
+		require.NoError(t, err)
+		require.Equal(t, uint8(255), u8)
+		f, err := unpack.AsFloat32(ctx, 1.5)
+		require.NoError(t, err)
+		require.Equal(t, float32(1.5), f)
+
+		_, err = unpack.AsInteger(ctx, "42")
+		require.ErrorIs(t, err, maputil.ErrInvalidType)
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Overflow", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		_, err := unpack.AsUint8(ctx, 256)
+		require.Equal(t, maputil.OverflowError{Value: "256", Type: "uint8"}, err)
+		_, err = unpack.AsInt16(ctx, -40000)
+		require.Equal(t, maputil.OverflowError{Value: "-40000", Type: "int16"}, err)
+		_, err = unpack.AsUint(ctx, -1)
+		require.Equal(t, maputil.OverflowError{Value: "-1", Type: "uint"}, err)
+		_, err = unpack.AsFloat32(ctx, 1e39)
+		require.Equal(t, maputil.OverflowError{Value: "1e+39", Type: "float32"}, err)
+	})
+	t.Run("Lenient", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Lenient = true
+		b, err := unpack.AsBoolean(ctx, "yes")
+		require.NoError(t, err)
+		require.True(t, b)
+		u16, err := unpack.AsUint16(ctx, "8080")
+		require.NoError(t, err)
+		require.Equal(t, uint16(8080), u16)
+		f, err := unpack.AsFloat32(ctx, "0.25")
+		require.NoError(t, err)
+		require.Equal(t, float32(0.25), f)
+
+		u64, err := unpack.AsUint64(ctx, "18446744073709551615")
+		require.NoError(t, err)
+		require.Equal(t, uint64(18446744073709551615), u64)
+
+		_, err = unpack.AsUint8(ctx, "300")
+		require.Equal(t, maputil.OverflowError{Value: "300", Type: "uint8"}, err)
+		_, err = unpack.AsInt32(ctx, "many")
+		require.ErrorIs(t, err, maputil.ErrInvalidValue)
+	})
+}
@@ -0,0 +1,312 @@
+package unpack
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/internal/tags"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// Unpacker is implemented by types which unpack themselves from a JSON-like
+// value.
+//
+// Decode, RequireDecode and OptionalDecode delegate to UnpackMap for any value
+// whose pointer implements Unpacker, with the context path positioned at the
+// value. Implementations should report problems to the context rather than
+// returning them, using the other functions of this package as needed.
+type Unpacker interface {
+	UnpackMap(ctx *errctx.Context, v interface{})
+}
+
+var unpackerType = reflect.TypeOf((*Unpacker)(nil)).Elem()
+
+// Decode populates the value pointed to by target from the map, sending any
+// errors to the given context.
+//
+// Struct fields are matched to keys using the `map` struct tag, falling back
+// to the field name if no tag is present. The tag holds the key name followed
+// by a comma separated list of options:
+//
+//	Port int    `map:"port,required"`
+//	Mode string `map:"mode,enum=a|b|c"`
+//	Name string `map:"name,default=foo"`
+//
+// The `required` option reports a MissingRequiredValueError if the key is not
+// present, the `enum` option restricts string values to the given set, and
+// the `default` option gives the value to use if the key is not present. A
+// tag of "-" skips the field entirely.
+//
+// Nested structs, slices, maps with string keys, pointers and interface{}
+// values are populated recursively, and types implementing Unpacker unpack
+// themselves. Unlike encoding/json, decoding does not
+// stop at the first error; every problem is reported to the context with the
+// path of the offending value, and values which fail to convert are left as
+// their zero value.
+//
+// If the context is lenient, strings are parsed for boolean and numeric
+// fields, as with RequireBoolean and RequireInteger.
+func Decode(ctx *errctx.Context, m map[string]interface{}, target interface{}) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		ctx.Error(InvalidTargetError{Type: reflect.TypeOf(target)})
+		return
+	}
+	decodeValue(ctx, m, rv.Elem())
+}
+
+// RequireDecode fetches a value from the map and decodes it into the value
+// pointed to by target, sending any errors to the given context.
+//
+// The target may be any type supported by Decode, including an Unpacker or a
+// slice of Unpacker values.
+func RequireDecode(ctx *errctx.Context, m map[string]interface{}, key string, target interface{}) {
+	defer ctx.UseKey(m, key)
+	v, ok := m[key]
+	if !ok {
+		ctx.ErrorWithKey(maputil.MissingRequiredValueError{Key: key}, key)
+		return
+	}
+	decodeKey(ctx, key, v, target)
+}
+
+// OptionalDecode fetches a value from the map and decodes it into the value
+// pointed to by target if it is present, sending any errors to the given
+// context.
+//
+// The target is left unchanged if the key is not present. The return value
+// indicates if the key was present.
+func OptionalDecode(ctx *errctx.Context, m map[string]interface{}, key string, target interface{}) bool {
+	defer ctx.UseKey(m, key)
+	v, ok := m[key]
+	if !ok {
+		return false
+	}
+	decodeKey(ctx, key, v, target)
+	return true
+}
+
+func decodeKey(ctx *errctx.Context, key string, v interface{}, target interface{}) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		ctx.Error(InvalidTargetError{Type: reflect.TypeOf(target)})
+		return
+	}
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	decodeValue(ctx, v, rv.Elem())
+}
+
+// decodeValue sets rv from the JSON-like value v, reporting errors at the
+// current path of the context.
+func decodeValue(ctx *errctx.Context, v interface{}, rv reflect.Value) {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() && rv.Addr().Type().Implements(unpackerType) {
+		rv.Addr().Interface().(Unpacker).UnpackMap(ctx, v)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			ctx.Error(UnsupportedTypeError{Type: rv.Type()})
+			return
+		}
+		if v == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return
+		}
+		rv.Set(reflect.ValueOf(v))
+	case reflect.Ptr:
+		if v == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		decodeValue(ctx, v, rv.Elem())
+	case reflect.Bool:
+		b, err := AsBoolean(ctx, v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := AsInteger(ctx, v)
+		if err != nil {
+			ctx.Error(overflowType(err, rv.Type().String()))
+			return
+		}
+		if rv.OverflowInt(i) {
+			ctx.Error(maputil.OverflowError{Value: strconv.FormatInt(i, 10), Type: rv.Type().String()})
+			return
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := lenient(ctx, v, parseUnsigned)
+		if err != nil {
+			ctx.Error(overflowType(err, rv.Type().String()))
+			return
+		}
+		u, err := maputil.AsUint64(v)
+		if err != nil {
+			ctx.Error(overflowType(err, rv.Type().String()))
+			return
+		}
+		if rv.OverflowUint(u) {
+			ctx.Error(maputil.OverflowError{Value: strconv.FormatUint(u, 10), Type: rv.Type().String()})
+			return
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := AsNumber(ctx, v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if rv.OverflowFloat(f) {
+			ctx.Error(maputil.OverflowError{Value: strconv.FormatFloat(f, 'g', -1, 64), Type: rv.Type().String()})
+			return
+		}
+		rv.SetFloat(f)
+	case reflect.String:
+		s, err := maputil.AsString(v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		rv.SetString(s)
+	case reflect.Slice:
+		a, err := maputil.AsArray(v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		decodeSlice(ctx, a, rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			ctx.Error(UnsupportedTypeError{Type: rv.Type()})
+			return
+		}
+		o, err := maputil.AsObject(v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		decodeMap(ctx, o, rv)
+	case reflect.Struct:
+		o, err := maputil.AsObject(v)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		decodeStruct(ctx, o, rv)
+	default:
+		ctx.Error(UnsupportedTypeError{Type: rv.Type()})
+	}
+}
+
+// overflowType replaces the type of an overflow error with the name of the
+// type being converted to.
+func overflowType(err error, name string) error {
+	if o, ok := err.(maputil.OverflowError); ok {
+		o.Type = name
+		return o
+	}
+	return err
+}
+
+func decodeSlice(ctx *errctx.Context, a []interface{}, rv reflect.Value) {
+	if a == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return
+	}
+
+	s := reflect.MakeSlice(rv.Type(), len(a), len(a))
+	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
+		scope := ctx.Enter(mpath.Index(i))
+		decodeValue(ctx, iv, s.Index(i))
+		ctx.Leave(scope)
+	}
+	rv.Set(s)
+}
+
+func decodeMap(ctx *errctx.Context, o map[string]interface{}, rv reflect.Value) {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	t := rv.Type()
+	r := reflect.MakeMapWithSize(t, len(o))
+	for _, k := range keys {
+		if ctx.Canceled() {
+			break
+		}
+		ev := reflect.New(t.Elem()).Elem()
+		scope := ctx.Enter(mpath.Key(k))
+		decodeValue(ctx, o[k], ev)
+		ctx.Leave(scope)
+		r.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), ev)
+	}
+	rv.Set(r)
+}
+
+func decodeStruct(ctx *errctx.Context, o map[string]interface{}, rv reflect.Value) {
+	for _, f := range tags.Fields(rv.Type()) {
+		if ctx.Canceled() {
+			return
+		}
+		v, ok := o[f.Name]
+		ctx.UseKey(o, f.Name)
+		if !ok {
+			if f.Required {
+				ctx.ErrorWithKey(maputil.MissingRequiredValueError{Key: f.Name}, f.Name)
+				continue
+			}
+			if !f.HasDefault {
+				continue
+			}
+			v = f.Default
+		}
+
+		scope := ctx.Enter(mpath.Key(f.Name))
+		fv := rv.FieldByIndex(f.Index)
+		count := ctx.ErrorCount()
+		decodeValue(ctx, v, fv)
+		if len(f.Enum) > 0 && ctx.ErrorCount() == count {
+			checkEnum(ctx, fv, f.Enum)
+		}
+		ctx.Leave(scope)
+	}
+}
+
+// checkEnum ensures that the decoded string value, or each string of a
+// decoded slice, is one of the allowed values. Strings which are not allowed
+// are reported and reset to the zero value.
+func checkEnum(ctx *errctx.Context, rv reflect.Value, allowed []string) {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			checkEnum(ctx, rv.Elem(), allowed)
+		}
+	case reflect.String:
+		if err := maputil.CheckEnum(rv.String(), allowed); err != nil {
+			ctx.Error(err)
+			rv.SetString("")
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			scope := ctx.Enter(mpath.Index(i))
+			checkEnum(ctx, rv.Index(i), allowed)
+			ctx.Leave(scope)
+		}
+	}
+}
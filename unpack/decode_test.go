@@ -0,0 +1,342 @@
+package unpack_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+type decodeTLS struct {
+	Enabled bool   `map:"enabled"`
+	Cert    string `map:"cert"`
+}
+
+type decodeServer struct {
+	Host    string            `map:"host,default=localhost"`
+	Port    uint16            `map:"port,required"`
+	Mode    string            `map:"mode,enum=a|b|c"`
+	Ratio   float32           `map:"ratio"`
+	Tags    []string          `map:"tags,enum=x|y"`
+	Labels  map[string]string `map:"labels"`
+	TLS     *decodeTLS        `map:"tls"`
+	Retries *int              `map:"retries,default=3"`
+	Extra   interface{}       `map:"extra"`
+	Ignored string            `map:"-"`
+}
+
+type decodeConfig struct {
+	Name    string         `map:"name,required"`
+	Servers []decodeServer `map:"servers"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	t.Run("Good", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"name": "test",
+			"servers": []interface{}{
+				map[string]interface{}{
+					"port":   8080,
+					"mode":   "b",
+					"ratio":  0.5,
+					"tags":   []interface{}{"x", "y"},
+					"labels": map[string]interface{}{"a": "A"},
+					"tls": map[string]interface{}{
+						"enabled": true,
+						"cert":    "cert.pem",
+					},
+					"extra": []interface{}{1, "two"},
+				},
+				map[string]interface{}{
+					"host":    "example.com",
+					"port":    int64(443),
+					"retries": 5,
+				},
+			},
+		}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		cfg := decodeConfig{}
+		unpack.Decode(ctx, m, &cfg)
+		require.Zero(t, ctx.ErrorCount())
+
+		three, five := 3, 5
+		require.Equal(t, decodeConfig{
+			Name: "test",
+			Servers: []decodeServer{
+				{
+					Host:    "localhost",
+					Port:    8080,
+					Mode:    "b",
+					Ratio:   0.5,
+					Tags:    []string{"x", "y"},
+					Labels:  map[string]string{"a": "A"},
+					TLS:     &decodeTLS{Enabled: true, Cert: "cert.pem"},
+					Retries: &three,
+					Extra:   []interface{}{1, "two"},
+				},
+				{
+					Host:    "example.com",
+					Port:    443,
+					Retries: &five,
+				},
+			},
+		}, cfg)
+	})
+	t.Run("AllErrors", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{
+					"port":   70000,
+					"mode":   "d",
+					"tags":   []interface{}{"x", "z"},
+					"labels": map[string]interface{}{"a": 1},
+					"tls":    map[string]interface{}{"enabled": "yes"},
+				},
+				"not-an-object",
+				map[string]interface{}{"port": -1, "ratio": "high"},
+			},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		cfg := decodeConfig{}
+		unpack.Decode(ctx, m, &cfg)
+		require.Equal(t, 9, ctx.ErrorCount())
+		require.Equal(t, []string{
+			`name: missing required value "name"`,
+			`servers[0].port: invalid value 70000; overflows uint16`,
+			`servers[0].mode: invalid value "d"; expected one of "a", "b", or "c"`,
+			`servers[0].tags[1]: invalid value "z"; expected "x" or "y"`,
+			`servers[0].labels.a: invalid type integer; expected string`,
+			`servers[0].tls.enabled: invalid type string; expected boolean`,
+			`servers[1]: invalid type string; expected object`,
+			`servers[2].port: invalid value -1; overflows uint16`,
+			`servers[2].ratio: invalid type string; expected number`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+		require.Len(t, cfg.Servers, 3)
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("WideIntegers", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"u": uint64(1) << 63, "i": uint64(1) << 63, "f": 1e300}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		target := struct {
+			U uint64 `map:"u"`
+			I int64  `map:"i"`
+			F int32  `map:"f"`
+		}{}
+		unpack.Decode(ctx, m, &target)
+		require.Equal(t, uint64(1)<<63, target.U)
+		require.Equal(t, []string{
+			`i: invalid value 9223372036854775808; overflows int64`,
+			`f: invalid value 1e+300; overflows int32`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Map", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"a": 1, "b": 2}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		target := map[string]int{}
+		unpack.Decode(ctx, m, &target)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, map[string]int{"a": 1, "b": 2}, target)
+	})
+	t.Run("Embedded", func(t *testing.T) {
+		t.Parallel()
+		type base struct {
+			ID string `map:"id"`
+		}
+		type derived struct {
+			base
+			Name string `map:"name"`
+		}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		target := derived{}
+		unpack.Decode(ctx, map[string]interface{}{"id": "1", "name": "n"}, &target)
+		require.Zero(t, ctx.ErrorCount())
+		require.Equal(t, derived{base: base{ID: "1"}, Name: "n"}, target)
+	})
+	t.Run("NullPointer", func(t *testing.T) {
+		t.Parallel()
+		type nullable struct {
+			Value *string `map:"value"`
+		}
+		s := "x"
+		target := nullable{Value: &s}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		unpack.Decode(ctx, map[string]interface{}{"value": nil}, &target)
+		require.Zero(t, ctx.ErrorCount())
+		require.Nil(t, target.Value)
+	})
+	t.Run("KeyTracking", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"name": "n", "srvers": []interface{}{}}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Keys = errctx.NewKeyTracker()
+		unpack.Decode(ctx, m, &decodeConfig{})
+		unpack.CheckUnknownKeys(ctx, m)
+		require.Equal(t, "srvers: unknown key \"srvers\"; did you mean \"servers\"?\n", sb.String())
+	})
+	t.Run("InvalidTarget", func(t *testing.T) {
+		t.Parallel()
+		t.Run("Nil", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New(errctx.ErrorDiscarder{})
+			unpack.Decode(ctx, map[string]interface{}{}, nil)
+			require.True(t, errors.Is(ctx.LastError(), unpack.ErrInvalidTarget))
+		})
+		t.Run("NotPointer", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New(errctx.ErrorDiscarder{})
+			unpack.Decode(ctx, map[string]interface{}{}, decodeConfig{})
+			require.True(t, errors.Is(ctx.LastError(), unpack.ErrInvalidTarget))
+		})
+		t.Run("NilPointer", func(t *testing.T) {
+			t.Parallel()
+			ctx := errctx.New(errctx.ErrorDiscarder{})
+			unpack.Decode(ctx, map[string]interface{}{}, (*decodeConfig)(nil))
+			require.True(t, errors.Is(ctx.LastError(), unpack.ErrInvalidTarget))
+		})
+		t.Run("Unsupported", func(t *testing.T) {
+			t.Parallel()
+			type unsupported struct {
+				C chan int `map:"c"`
+			}
+			ctx := errctx.New(errctx.ErrorDiscarder{})
+			unpack.Decode(ctx, map[string]interface{}{"c": 1}, &unsupported{})
+			require.True(t, errors.Is(ctx.LastError(), unpack.ErrInvalidTarget))
+		})
+	})
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+	t.Run("InvalidTarget", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, "invalid decode target nil", unpack.InvalidTargetError{}.Error())
+		require.Equal(
+			t, "invalid decode target int; expected a non-nil pointer",
+			unpack.InvalidTargetError{Type: reflect.TypeOf(0)}.Error(),
+		)
+	})
+}
+
+type testPort uint16
+
+func (p *testPort) UnpackMap(ctx *errctx.Context, v interface{}) {
+	i, err := maputil.AsInteger(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if i < 1 || i > 65535 {
+		ctx.Error(maputil.EnumStringError{Value: strconv.FormatInt(i, 10)})
+		return
+	}
+	*p = testPort(i)
+}
+
+type testSelector struct {
+	Key   string
+	Value string
+}
+
+func (s *testSelector) UnpackMap(ctx *errctx.Context, v interface{}) {
+	str, err := maputil.AsString(v)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	parts := strings.SplitN(str, "=", 2)
+	if len(parts) != 2 {
+		ctx.Error(maputil.EnumStringError{Value: str})
+		return
+	}
+	s.Key, s.Value = parts[0], parts[1]
+}
+
+type unpackerConfig struct {
+	Port      testPort                `map:"port"`
+	Backup    *testPort               `map:"backup"`
+	Selectors []testSelector          `map:"selectors"`
+	Named     map[string]testSelector `map:"named"`
+}
+
+func TestUnpacker(t *testing.T) {
+	t.Parallel()
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"port":      80,
+			"backup":    8080,
+			"selectors": []interface{}{"a=b", "bad", "c=d"},
+			"named":     map[string]interface{}{"x": "k=v"},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		cfg := unpackerConfig{}
+		unpack.Decode(ctx, m, &cfg)
+		backup := testPort(8080)
+		require.Equal(t, unpackerConfig{
+			Port:      80,
+			Backup:    &backup,
+			Selectors: []testSelector{{"a", "b"}, {}, {"c", "d"}},
+			Named:     map[string]testSelector{"x": {"k", "v"}},
+		}, cfg)
+		require.Equal(t, "selectors[1]: invalid value \"bad\"\n", sb.String())
+	})
+	t.Run("DecodeTarget", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		s := testSelector{}
+		unpack.Decode(ctx, map[string]interface{}{}, &s)
+		require.Equal(t, 1, ctx.ErrorCount())
+		require.True(t, errors.Is(ctx.LastError(), maputil.ErrInvalidType))
+	})
+	t.Run("RequireDecode", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"port":  70000,
+			"ports": []interface{}{1, 2, 0},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		var port testPort
+		unpack.RequireDecode(ctx, m, "port", &port)
+		var ports []testPort
+		unpack.RequireDecode(ctx, m, "ports", &ports)
+		unpack.RequireDecode(ctx, m, "missing", &port)
+		require.Equal(t, []testPort{1, 2, 0}, ports)
+		require.Equal(
+			t, "port: invalid value \"70000\"\n"+
+				"ports[2]: invalid value \"0\"\n"+
+				"missing: missing required value \"missing\"\n",
+			sb.String(),
+		)
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("OptionalDecode", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"sel": "a=b"}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		s := testSelector{Key: "default"}
+		require.False(t, unpack.OptionalDecode(ctx, m, "missing", &s))
+		require.Equal(t, "default", s.Key)
+		require.True(t, unpack.OptionalDecode(ctx, m, "sel", &s))
+		require.Equal(t, testSelector{"a", "b"}, s)
+		require.Zero(t, ctx.ErrorCount())
+
+		unpack.OptionalDecode(ctx, m, "sel", s)
+		require.True(t, errors.Is(ctx.LastError(), unpack.ErrInvalidTarget))
+	})
+}
@@ -0,0 +1,46 @@
+package unpack
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/tvarney/maputil/consterr"
+)
+
+// ErrInvalidTarget is the root error of an invalid decode target.
+const ErrInvalidTarget consterr.Error = "invalid decode target"
+
+// InvalidTargetError is an error indicating that the target given to Decode
+// was not a non-nil pointer.
+type InvalidTargetError struct {
+	Type reflect.Type
+}
+
+// Error returns the string representation of this invalid target error.
+func (e InvalidTargetError) Error() string {
+	if e.Type == nil {
+		return string(ErrInvalidTarget) + " nil"
+	}
+	return fmt.Sprintf("%s %s; expected a non-nil pointer", string(ErrInvalidTarget), e.Type)
+}
+
+// Unwrap returns the parent error for this invalid target error.
+func (e InvalidTargetError) Unwrap() error {
+	return ErrInvalidTarget
+}
+
+// UnsupportedTypeError is an error indicating that Decode encountered a Go
+// type which can not be populated from a JSON-like value.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+// Error returns the string representation of this unsupported type error.
+func (e UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%s %s; unsupported Go type", string(ErrInvalidTarget), e.Type)
+}
+
+// Unwrap returns the parent error for this unsupported type error.
+func (e UnsupportedTypeError) Unwrap() error {
+	return ErrInvalidTarget
+}
@@ -0,0 +1,41 @@
+package unpack_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestFormats(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"endpoint": "https://example.com/api",
+		"listen":   "0.0.0.0:8080",
+		"allow":    "10.0.0.0/8",
+		"dns":      "1.1.1.1",
+		"filter":   "^app-",
+		"key":      "c2VjcmV0",
+		"proxy":    "example.com",
+		"match":    "[",
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, "example.com", unpack.RequireURL(ctx, m, "endpoint").Host)
+	require.Equal(t, maputil.HostPort{Host: "0.0.0.0", Port: 8080}, unpack.RequireHostPort(ctx, m, "listen"))
+	require.True(t, unpack.RequireCIDR(ctx, m, "allow").Contains(net.IPv4(10, 1, 2, 3)))
+	require.Equal(t, "1.1.1.1", unpack.OptionalIP(ctx, m, "dns", nil).String())
+	require.True(t, unpack.RequireRegexp(ctx, m, "filter").MatchString("app-web"))
+	require.Equal(t, []byte("secret"), unpack.RequireBase64Bytes(ctx, m, "key"))
+	require.Nil(t, unpack.OptionalURL(ctx, m, "proxy", nil))
+	require.Nil(t, unpack.OptionalRegexp(ctx, m, "match", nil))
+	require.Equal(t, maputil.HostPort{Port: 53}, unpack.OptionalHostPort(ctx, m, "missing", maputil.HostPort{Port: 53}))
+	require.Equal(t, []string{
+		`proxy: invalid value "example.com" (missing scheme); expected absolute URL`,
+		`match: invalid value "[" (missing closing ]); expected regular expression`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
@@ -0,0 +1,39 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestSizedIntegers(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"port":    70000,
+		"retries": -1,
+		"level":   127,
+		"offset":  -40000,
+		"count":   1 << 32,
+		"id":      uint64(1) << 63,
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Zero(t, unpack.RequireUint16(ctx, m, "port"))
+	require.Equal(t, uint8(3), unpack.OptionalUint8(ctx, m, "retries", 3))
+	require.Equal(t, int8(127), unpack.RequireInt8(ctx, m, "level"))
+	require.Zero(t, unpack.OptionalInt16(ctx, m, "offset", 0))
+	require.Equal(t, int32(7), unpack.OptionalInt32(ctx, m, "missing", 7))
+	require.Zero(t, unpack.RequireUint32(ctx, m, "count"))
+	require.Equal(t, uint64(1)<<63, unpack.RequireUint64(ctx, m, "id"))
+	require.Zero(t, unpack.RequireInt32(ctx, m, "missing"))
+	require.Equal(t, []string{
+		`port: invalid value 70000; overflows uint16`,
+		`retries: invalid value -1; overflows uint8`,
+		`offset: invalid value -40000; overflows int16`,
+		`count: invalid value 4294967296; overflows uint32`,
+		`missing: missing required value "missing"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
@@ -0,0 +1,113 @@
+package unpack
+
+import (
+	"sort"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+)
+
+// CheckUnknownKeys reports every key of the map which was not read by the
+// unpack functions as an UnknownKeyError, sending the errors to the given
+// context.
+//
+// Key tracking must be enabled on the context by setting ctx.Keys before the
+// map is unpacked; if it is not, this function does nothing. Each unknown key
+// is reported with a suggestion if it is close to one of the known keys.
+func CheckUnknownKeys(ctx *errctx.Context, m map[string]interface{}) {
+	if ctx.Keys == nil {
+		return
+	}
+
+	unknown := ctx.Keys.Unused(m)
+	if len(unknown) == 0 {
+		return
+	}
+	known := ctx.Keys.Known(m)
+	for _, k := range unknown {
+		ctx.ErrorWithKey(maputil.UnknownKeyError{
+			Key:        k,
+			Suggestion: SuggestKey(k, known),
+		}, k)
+	}
+}
+
+// Leftovers reports every key remaining in the map as an UnknownKeyError,
+// sending the errors to the given context.
+//
+// This is intended for contexts with ctx.Consume set, where every key read by
+// the unpack functions is deleted; any keys left once the map has been
+// unpacked were not expected. If the context is also tracking keys, each key
+// is reported with a suggestion if it is close to one of the known keys.
+// Keys consumed by a forked context are only deleted once it is joined, so
+// Leftovers must be called after the join.
+func Leftovers(ctx *errctx.Context, m map[string]interface{}) {
+	if len(m) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var known []string
+	if ctx.Keys != nil {
+		known = ctx.Keys.Known(m)
+	}
+	for _, k := range keys {
+		ctx.ErrorWithKey(maputil.UnknownKeyError{
+			Key:        k,
+			Suggestion: SuggestKey(k, known),
+		}, k)
+	}
+}
+
+// SuggestKey returns the known key closest to the given key, or an empty
+// string if no known key is close enough to be a likely typo.
+func SuggestKey(key string, known []string) string {
+	limit := len([]rune(key)) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	best, bestDist := "", limit+1
+	for _, k := range known {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,130 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestCheckUnknownKeys(t *testing.T) {
+	t.Parallel()
+	t.Run("NoTracker", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		unpack.CheckUnknownKeys(ctx, map[string]interface{}{"a": 1})
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("AllKnown", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"port": 80, "host": "localhost"}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Keys = errctx.NewKeyTracker()
+		unpack.RequireInteger(ctx, m, "port")
+		unpack.OptionalString(ctx, m, "host", "")
+		unpack.CheckUnknownKeys(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"port":   80,
+			"timout": 10,
+			"zzz":    true,
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Keys = errctx.NewKeyTracker()
+		unpack.RequireInteger(ctx, m, "port")
+		unpack.OptionalInteger(ctx, m, "timeout", 30)
+		unpack.CheckUnknownKeys(ctx, m)
+		require.Equal(t, 2, ctx.ErrorCount())
+		require.Equal(
+			t, "timout: unknown key \"timout\"; did you mean \"timeout\"?\n"+
+				"zzz: unknown key \"zzz\"\n",
+			sb.String(),
+		)
+	})
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+		inner := map[string]interface{}{"nme": "x"}
+		m := map[string]interface{}{"inner": inner}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Keys = errctx.NewKeyTracker()
+		o := unpack.RequireObject(ctx, m, "inner")
+		unpack.OptionalString(ctx, o, "name", "")
+		unpack.CheckUnknownKeys(ctx, m)
+		require.Zero(t, ctx.ErrorCount())
+		unpack.CheckUnknownKeys(ctx, o)
+		require.Equal(t, "nme: unknown key \"nme\"; did you mean \"name\"?\n", sb.String())
+	})
+}
+
+func TestLeftovers(t *testing.T) {
+	t.Parallel()
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"port": 80}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		ctx.Consume = true
+		require.Equal(t, int64(80), unpack.RequireInteger(ctx, m, "port"))
+		unpack.Leftovers(ctx, m)
+		require.Empty(t, m)
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"server": map[string]interface{}{"host": "localhost", "prot": 80},
+			"debug":  "yes",
+			"zzz":    true,
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Consume = true
+		ctx.Lenient = true
+		unpack.RequireObjectFunc(ctx, m, "server", func(ctx *errctx.Context, o map[string]interface{}) {
+			unpack.RequireString(ctx, o, "host")
+			unpack.OptionalInteger(ctx, o, "port", 8080)
+			unpack.Leftovers(ctx, o)
+		})
+		require.True(t, unpack.OptionalBoolean(ctx, m, "debug", false))
+		unpack.Leftovers(ctx, m)
+		require.Equal(t, map[string]interface{}{"zzz": true}, m)
+		require.Equal(t, 2, ctx.ErrorCount())
+		require.Equal(
+			t, "server.prot: unknown key \"prot\"\n"+
+				"zzz: unknown key \"zzz\"\n",
+			sb.String(),
+		)
+	})
+	t.Run("Tracker", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"port": 80, "timout": 10}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Consume = true
+		ctx.Keys = errctx.NewKeyTracker()
+		unpack.RequireInteger(ctx, m, "port")
+		unpack.OptionalInteger(ctx, m, "timeout", 30)
+		unpack.Leftovers(ctx, m)
+		require.Equal(t, "timout: unknown key \"timout\"; did you mean \"timeout\"?\n", sb.String())
+	})
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+		var v struct {
+			Name string `map:"name"`
+		}
+		m := map[string]interface{}{"name": "x", "extra": 1}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Consume = true
+		unpack.Decode(ctx, m, &v)
+		unpack.Leftovers(ctx, m)
+		require.Equal(t, "x", v.Name)
+		require.Equal(t, "extra: unknown key \"extra\"\n", sb.String())
+	})
+}
@@ -0,0 +1,93 @@
+package unpack
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+)
+
+// parser parses a string read by a lenient context, returning the value to
+// convert in its place.
+type parser func(s string) (interface{}, error)
+
+func parseBoolean(s string) (interface{}, error) {
+	return maputil.ParseBoolean(s)
+}
+
+func parseInteger(s string) (interface{}, error) {
+	return maputil.ParseInteger(s)
+}
+
+// parseUnsigned parses integers too large for an int64 as a uint64, so that
+// the unsigned conversions accept the whole of their range.
+func parseUnsigned(s string) (interface{}, error) {
+	i, err := maputil.ParseInteger(s)
+	if _, ok := err.(maputil.OverflowError); ok {
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, maputil.OverflowError{Value: s, Type: "uint64"}
+		}
+		return u, nil
+	}
+	return i, err
+}
+
+func parseNumber(s string) (interface{}, error) {
+	return maputil.ParseNumber(s)
+}
+
+func parseNull(s string) (interface{}, error) {
+	return nil, maputil.ParseNull(s)
+}
+
+// parsePercent parses strings without a percent sign as numbers, leaving the
+// rest to maputil.AsPercent, which accepts them as they are.
+func parsePercent(s string) (interface{}, error) {
+	if strings.HasSuffix(strings.TrimSpace(s), "%") {
+		return s, nil
+	}
+	return maputil.ParseNumber(s)
+}
+
+// lenient returns the value to convert in place of v. If the context is
+// lenient and v is a string, the string is parsed with parse; otherwise v is
+// returned unchanged.
+//
+// Every boolean, numeric and null conversion in this package passes its value
+// through lenient. Conversions which are made from strings in any case, such
+// as big numbers, durations, byte sizes and times, do not.
+func lenient(ctx *errctx.Context, v interface{}, parse parser) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok || !ctx.Lenient {
+		return v, nil
+	}
+	return parse(s)
+}
+
+// lenientMap returns the map to read the key from in place of m, so that the
+// maputil accessors convert the value returned by lenient. If the context is
+// lenient and the key is present, this is a new map holding only the key; m
+// itself is never modified, so the Pop functions delete the key from m
+// themselves.
+//
+// If the value can not be parsed the error is sent to the context and false
+// is returned.
+func lenientMap(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	parse parser,
+) (map[string]interface{}, bool) {
+	v, ok := m[key]
+	if !ok || !ctx.Lenient {
+		return m, true
+	}
+	v, err := lenient(ctx, v, parse)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, false
+	}
+	return map[string]interface{}{key: v}, true
+}
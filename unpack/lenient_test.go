@@ -0,0 +1,142 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestLenient(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"debug":   "true",
+		"port":    "8080",
+		"ratio":   "0.5",
+		"proxy":   "",
+		"workers": "four",
+		"verbose": "sometimes",
+		"limit":   "1.5",
+		"native":  3,
+		"max":     "18446744073709551615",
+	}
+
+	t.Run("Strict", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		require.Zero(t, unpack.RequireInteger(ctx, m, "port"))
+		require.Equal(t, []string{
+			`port: invalid type string; expected integer`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Lenient", func(t *testing.T) {
+		t.Parallel()
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Lenient = true
+		require.True(t, unpack.RequireBoolean(ctx, m, "debug"))
+		require.Equal(t, int64(8080), unpack.RequireInteger(ctx, m, "port"))
+		require.Equal(t, 0.5, unpack.RequireNumber(ctx, m, "ratio"))
+		unpack.RequireNull(ctx, m, "proxy")
+		unpack.OptionalNull(ctx, m, "proxy")
+		require.Equal(t, int64(3), unpack.RequireInteger(ctx, m, "native"))
+		require.Equal(t, int64(1), unpack.OptionalInteger(ctx, m, "workers", 1))
+		require.True(t, unpack.OptionalBoolean(ctx, m, "verbose", true))
+		require.Equal(t, 2.0, unpack.OptionalNumber(ctx, m, "missing", 2))
+		require.Zero(t, unpack.RequireInteger(ctx, m, "limit"))
+		require.Equal(t, []string{
+			`workers: invalid value "four"; cannot parse as integer`,
+			`verbose: invalid value "sometimes"; cannot parse as boolean`,
+			`limit: invalid value "1.5"; cannot parse as integer`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Sized", func(t *testing.T) {
+		t.Parallel()
+		sized := map[string]interface{}{
+			"port":  "8080",
+			"small": "300",
+			"ratio": "0.25",
+			"share": "50%",
+			"debug": "yes",
+			"max":   "18446744073709551615",
+			"huge":  "18446744073709551616",
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Lenient = true
+		require.Equal(t, uint16(8080), unpack.RequireUint16(ctx, sized, "port"))
+		require.Equal(t, int64(8080), unpack.RequireIntegerRange(ctx, sized, "port", maputil.IntegerBetween(1024, 65535)))
+		require.Equal(t, uint8(7), unpack.OptionalUint8(ctx, sized, "small", 7))
+		require.Equal(t, 0.25, unpack.RequireNumberRange(ctx, sized, "ratio", maputil.NumberBetween(0, 1)))
+		require.Equal(t, 0.25, unpack.RequirePercent(ctx, sized, "ratio"))
+		require.Equal(t, 0.5, unpack.OptionalPercent(ctx, sized, "share", 0))
+		require.Equal(t, "8080", sized["port"])
+		require.Equal(t, uint64(18446744073709551615), unpack.RequireUint64(ctx, sized, "max"))
+		require.Zero(t, unpack.RequireUint64(ctx, sized, "huge"))
+
+		u, ok := unpack.PopUint32(ctx, sized, "port")
+		require.True(t, ok)
+		require.Equal(t, uint32(8080), u)
+		b, ok := unpack.PopBoolean(ctx, sized, "debug")
+		require.True(t, ok)
+		require.True(t, b)
+		require.NotContains(t, sized, "port")
+		require.NotContains(t, sized, "debug")
+		require.Equal(t, []string{
+			`small: invalid value 300; overflows uint8`,
+			`huge: invalid value 18446744073709551616; overflows uint64`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Elements", func(t *testing.T) {
+		t.Parallel()
+		elems := map[string]interface{}{
+			"ports":  []interface{}{"80", "443", "http"},
+			"flags":  map[string]interface{}{"a": "true", "b": "no"},
+			"ratios": []interface{}{"0.5", 1},
+			"grid":   []interface{}{[]interface{}{"1", "2"}},
+			"pair":   []interface{}{"1", "true"},
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Lenient = true
+		require.Equal(t, []int64{80, 443}, unpack.RequireIntegerArray(ctx, elems, "ports"))
+		require.Equal(t, map[string]bool{"a": true, "b": false}, unpack.RequireBooleanMap(ctx, elems, "flags"))
+		require.Equal(t, []float64{0.5, 1}, unpack.OptionalNumberArray(ctx, elems, "ratios"))
+		require.Equal(t, [][]int64{{1, 2}}, unpack.RequireIntegerMatrix(ctx, elems, "grid"))
+
+		var i int64
+		var flag bool
+		require.True(t, unpack.RequireTuple(ctx, elems, "pair", maputil.LengthBetween(2, 2),
+			unpack.IntegerElement(&i), unpack.BooleanElement(&flag)))
+		require.Equal(t, int64(1), i)
+		require.True(t, flag)
+		require.Equal(t, []string{
+			`ports[2]: invalid value "http"; cannot parse as integer`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+		var cfg struct {
+			Debug   bool    `map:"debug"`
+			Port    uint16  `map:"port"`
+			Max     uint64  `map:"max"`
+			Ratio   float32 `map:"ratio"`
+			Workers int     `map:"workers"`
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Lenient = true
+		unpack.Decode(ctx, m, &cfg)
+		require.True(t, cfg.Debug)
+		require.Equal(t, uint16(8080), cfg.Port)
+		require.Equal(t, uint64(18446744073709551615), cfg.Max)
+		require.Equal(t, float32(0.5), cfg.Ratio)
+		require.Equal(t, []string{
+			`workers: invalid value "four"; cannot parse as integer`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+}
@@ -0,0 +1,107 @@
+package unpack
+
+import (
+	"sort"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// eachValue calls fn for each key of the object in sorted order, with the
+// context path positioned under the key of the object, sending any errors
+// returned by fn to the context at the path of the value.
+func eachValue(ctx *errctx.Context, key string, o map[string]interface{}, fn func(k string, v interface{}) error) {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	for _, k := range keys {
+		if ctx.Canceled() {
+			return
+		}
+		v := o[k]
+		ctx.UseKey(o, k)
+		if err := fn(k, v); err != nil {
+			ctx.ErrorWithKey(err, k)
+		}
+	}
+}
+
+func booleanMap(ctx *errctx.Context, key string, o map[string]interface{}) map[string]bool {
+	if len(o) == 0 {
+		return nil
+	}
+	bm := make(map[string]bool, len(o))
+	eachValue(ctx, key, o, func(k string, iv interface{}) error {
+		b, err := AsBoolean(ctx, iv)
+		if err == nil {
+			bm[k] = b
+		}
+		return err
+	})
+	return bm
+}
+
+func integerMap(ctx *errctx.Context, key string, o map[string]interface{}) map[string]int64 {
+	if len(o) == 0 {
+		return nil
+	}
+	im := make(map[string]int64, len(o))
+	eachValue(ctx, key, o, func(k string, iv interface{}) error {
+		i, err := AsInteger(ctx, iv)
+		if err == nil {
+			im[k] = i
+		}
+		return err
+	})
+	return im
+}
+
+func numberMap(ctx *errctx.Context, key string, o map[string]interface{}) map[string]float64 {
+	if len(o) == 0 {
+		return nil
+	}
+	nm := make(map[string]float64, len(o))
+	eachValue(ctx, key, o, func(k string, iv interface{}) error {
+		n, err := AsNumber(ctx, iv)
+		if err == nil {
+			nm[k] = n
+		}
+		return err
+	})
+	return nm
+}
+
+func objectMap(ctx *errctx.Context, key string, o map[string]interface{}) map[string]map[string]interface{} {
+	if len(o) == 0 {
+		return nil
+	}
+	om := make(map[string]map[string]interface{}, len(o))
+	eachValue(ctx, key, o, func(k string, iv interface{}) error {
+		obj, err := maputil.AsObject(iv)
+		if err == nil {
+			om[k] = obj
+		}
+		return err
+	})
+	return om
+}
+
+func stringMap(ctx *errctx.Context, key string, o map[string]interface{}) map[string]string {
+	if len(o) == 0 {
+		return nil
+	}
+	sm := make(map[string]string, len(o))
+	eachValue(ctx, key, o, func(k string, iv interface{}) error {
+		s, err := maputil.AsString(iv)
+		if err == nil {
+			sm[k] = s
+		}
+		return err
+	})
+	return sm
+}
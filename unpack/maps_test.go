@@ -0,0 +1,51 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestTypedMaps(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"labels":   map[string]interface{}{"app": "web", "tier": 2, "env": "prod"},
+		"limits":   map[string]interface{}{"cpu": 2, "memory": "lots"},
+		"weights":  map[string]interface{}{"a": 0.5, "b": 1},
+		"features": map[string]interface{}{"beta": true, "dark": "yes"},
+		"backends": map[string]interface{}{
+			"primary":   map[string]interface{}{"host": "db1"},
+			"secondary": "db2",
+		},
+		"empty": map[string]interface{}{},
+		"list":  []interface{}{},
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	ctx.Keys = errctx.NewKeyTracker()
+	require.Equal(t, map[string]string{"app": "web", "env": "prod"}, unpack.RequireStringMap(ctx, m, "labels"))
+	require.Equal(t, map[string]int64{"cpu": 2}, unpack.RequireIntegerMap(ctx, m, "limits"))
+	require.Equal(t, map[string]float64{"a": 0.5, "b": 1}, unpack.OptionalNumberMap(ctx, m, "weights"))
+	require.Equal(t, map[string]bool{"beta": true}, unpack.OptionalBooleanMap(ctx, m, "features"))
+	require.Equal(t, map[string]map[string]interface{}{
+		"primary": {"host": "db1"},
+	}, unpack.RequireObjectMap(ctx, m, "backends"))
+	require.Nil(t, unpack.RequireStringMap(ctx, m, "empty"))
+	require.Nil(t, unpack.OptionalStringMap(ctx, m, "missing"))
+	require.Nil(t, unpack.OptionalIntegerMap(ctx, m, "list"))
+	require.Nil(t, unpack.RequireBooleanMap(ctx, m, "missing"))
+	require.Equal(t, []string{
+		`labels.tier: invalid type integer; expected string`,
+		`limits.memory: invalid type string; expected integer`,
+		`features.dark: invalid type string; expected boolean`,
+		`backends.secondary: invalid type string; expected object`,
+		`list: invalid type array; expected object`,
+		`missing: missing required value "missing"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+
+	// Every key of the inner objects was read.
+	require.Empty(t, ctx.Keys.Unused(m["labels"].(map[string]interface{})))
+}
@@ -1,6 +1,12 @@
 package unpack
 
 import (
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"time"
+
 	"github.com/tvarney/maputil"
 	"github.com/tvarney/maputil/errctx"
 	"github.com/tvarney/maputil/mpath"
@@ -9,23 +15,230 @@ import (
 // OptionalArray fetches a value from the map and converts it to an array,
 // sending any errors to the given context.
 func OptionalArray(ctx *errctx.Context, m map[string]interface{}, key string, dv []interface{}) []interface{} {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, dv)
 	ctx.ErrorWithKey(err, key)
 	return a
 }
 
+// OptionalArrayLength fetches a value from the map and converts it to an array
+// and ensures its length is within the range, sending any errors to the given
+// context.
+func OptionalArrayLength(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.LengthRange,
+	dv []interface{},
+) []interface{} {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArrayLength(m, key, r, dv)
+	ctx.ErrorWithKey(err, key)
+	return a
+}
+
+// OptionalBase64Bytes fetches a value from the map and converts it to bytes
+// encoded as a base64 string, sending any errors to the given context.
+func OptionalBase64Bytes(ctx *errctx.Context, m map[string]interface{}, key string, dv []byte) []byte {
+	defer ctx.UseKey(m, key)
+	b, err := maputil.OptionalBase64Bytes(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return b
+}
+
+// OptionalBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number, sending any errors to the given
+// context.
+func OptionalBigFloat(ctx *errctx.Context, m map[string]interface{}, key string, dv *big.Float) *big.Float {
+	defer ctx.UseKey(m, key)
+	f, err := maputil.OptionalBigFloat(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// OptionalBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer, sending any errors to the given context.
+func OptionalBigInt(ctx *errctx.Context, m map[string]interface{}, key string, dv *big.Int) *big.Int {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.OptionalBigInt(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
 // OptionalBoolean fetches a value from the map and converts it to a boolean,
 // sending any errors to the given context.
 func OptionalBoolean(ctx *errctx.Context, m map[string]interface{}, key string, dv bool) bool {
-	b, err := maputil.OptionalBoolean(m, key, dv)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseBoolean)
+	if !ok {
+		return dv
+	}
+	b, err := maputil.OptionalBoolean(src, key, dv)
 	ctx.ErrorWithKey(err, key)
 	return b
 }
 
+// OptionalBooleanMap fetches an object from the map and attempts to convert
+// all of its values to booleans, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// booleans, possibly resulting in a map with fewer keys than the object in the
+// map.
+func OptionalBooleanMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]bool {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.OptionalObject(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return booleanMap(ctx, key, o)
+}
+
+// OptionalByteSize fetches a value from the map and converts it to a number of
+// bytes, sending any errors to the given context.
+func OptionalByteSize(ctx *errctx.Context, m map[string]interface{}, key string, dv int64) int64 {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.OptionalByteSize(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// OptionalByteSizeRange fetches a value from the map, converts it to a number
+// of bytes and ensures it is within the range, sending any errors to the given
+// context.
+func OptionalByteSizeRange(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.IntegerRange,
+	dv int64,
+) int64 {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.OptionalByteSizeRange(m, key, r, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// OptionalCIDR fetches a value from the map and converts it to an IP network,
+// sending any errors to the given context.
+func OptionalCIDR(ctx *errctx.Context, m map[string]interface{}, key string, dv *net.IPNet) *net.IPNet {
+	defer ctx.UseKey(m, key)
+	n, err := maputil.OptionalCIDR(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return n
+}
+
+// OptionalDuration fetches a value from the map and converts it to a duration,
+// sending any errors to the given context.
+func OptionalDuration(ctx *errctx.Context, m map[string]interface{}, key string, unit, dv time.Duration) time.Duration {
+	defer ctx.UseKey(m, key)
+	d, err := maputil.OptionalDuration(m, key, unit, dv)
+	ctx.ErrorWithKey(err, key)
+	return d
+}
+
+// OptionalHostPort fetches a value from the map and converts it to a host and
+// port, sending any errors to the given context.
+func OptionalHostPort(ctx *errctx.Context, m map[string]interface{}, key string, dv maputil.HostPort) maputil.HostPort {
+	defer ctx.UseKey(m, key)
+	hp, err := maputil.OptionalHostPort(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return hp
+}
+
+// OptionalIP fetches a value from the map and converts it to an IP address,
+// sending any errors to the given context.
+func OptionalIP(ctx *errctx.Context, m map[string]interface{}, key string, dv net.IP) net.IP {
+	defer ctx.UseKey(m, key)
+	ip, err := maputil.OptionalIP(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return ip
+}
+
+// OptionalInt8 fetches a value from the map and converts it to an 8-bit
+// integer, sending any errors to the given context.
+func OptionalInt8(ctx *errctx.Context, m map[string]interface{}, key string, dv int8) int8 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return dv
+	}
+	i, err := maputil.OptionalInt8(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// OptionalInt16 fetches a value from the map and converts it to a 16-bit
+// integer, sending any errors to the given context.
+func OptionalInt16(ctx *errctx.Context, m map[string]interface{}, key string, dv int16) int16 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return dv
+	}
+	i, err := maputil.OptionalInt16(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// OptionalInt32 fetches a value from the map and converts it to a 32-bit
+// integer, sending any errors to the given context.
+func OptionalInt32(ctx *errctx.Context, m map[string]interface{}, key string, dv int32) int32 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return dv
+	}
+	i, err := maputil.OptionalInt32(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
 // OptionalInteger fetches a value from the map and converts it to an integer,
 // sending any errors to the given context.
 func OptionalInteger(ctx *errctx.Context, m map[string]interface{}, key string, dv int64) int64 {
-	i, err := maputil.OptionalInteger(m, key, dv)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return dv
+	}
+	i, err := maputil.OptionalInteger(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// OptionalIntegerMap fetches an object from the map and attempts to convert
+// all of its values to integers, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// integers, possibly resulting in a map with fewer keys than the object in the
+// map.
+func OptionalIntegerMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]int64 {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.OptionalObject(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return integerMap(ctx, key, o)
+}
+
+// OptionalIntegerRange fetches a value from the map and converts it to an
+// integer and ensures it is within the range, sending any errors to the given
+// context.
+func OptionalIntegerRange(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.IntegerRange,
+	dv int64,
+) int64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return dv
+	}
+	i, err := maputil.OptionalIntegerRange(src, key, r, dv)
 	ctx.ErrorWithKey(err, key)
 	return i
 }
@@ -33,13 +246,58 @@ func OptionalInteger(ctx *errctx.Context, m map[string]interface{}, key string,
 // OptionalNull fetches a value from the map and ensures it is nil, sending any
 // errors to the given context.
 func OptionalNull(ctx *errctx.Context, m map[string]interface{}, key string) {
-	ctx.ErrorWithKey(maputil.OptionalNull(m, key), key)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNull)
+	if !ok {
+		return
+	}
+	ctx.ErrorWithKey(maputil.OptionalNull(src, key), key)
 }
 
 // OptionalNumber fetches a value from the map and converts it to a number,
 // sending any errors to the given context.
 func OptionalNumber(ctx *errctx.Context, m map[string]interface{}, key string, dv float64) float64 {
-	n, err := maputil.OptionalNumber(m, key, dv)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNumber)
+	if !ok {
+		return dv
+	}
+	n, err := maputil.OptionalNumber(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return n
+}
+
+// OptionalNumberMap fetches an object from the map and attempts to convert all
+// of its values to numbers, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// numbers, possibly resulting in a map with fewer keys than the object in the
+// map.
+func OptionalNumberMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]float64 {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.OptionalObject(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return numberMap(ctx, key, o)
+}
+
+// OptionalNumberRange fetches a value from the map and converts it to a number
+// and ensures it is within the range, sending any errors to the given context.
+func OptionalNumberRange(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.NumberRange,
+	dv float64,
+) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNumber)
+	if !ok {
+		return dv
+	}
+	n, err := maputil.OptionalNumberRange(src, key, r, dv)
 	ctx.ErrorWithKey(err, key)
 	return n
 }
@@ -52,14 +310,104 @@ func OptionalObject(
 	key string,
 	dv map[string]interface{},
 ) map[string]interface{} {
+	defer ctx.UseKey(m, key)
 	o, err := maputil.OptionalObject(m, key, dv)
 	ctx.ErrorWithKey(err, key)
 	return o
 }
 
+// OptionalObjectFunc fetches a value from the map, converts it to an object,
+// and calls fn with the context path positioned under the key, sending any
+// errors to the given context.
+//
+// The function is not called if the value is missing or is not an object.
+// The return value indicates if the function was called.
+func OptionalObjectFunc(ctx *errctx.Context, m map[string]interface{}, key string, fn ObjectFunc) bool {
+	defer ctx.UseKey(m, key)
+	o, ok, err := maputil.GetObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	fn(ctx, o)
+	return true
+}
+
+// OptionalObjectMap fetches an object from the map and attempts to convert all
+// of its values to objects, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// objects, possibly resulting in a map with fewer keys than the object in the
+// map.
+func OptionalObjectMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]map[string]interface{} {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.OptionalObject(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return objectMap(ctx, key, o)
+}
+
+// OptionalPercent fetches a value from the map and converts it to a fraction,
+// sending any errors to the given context.
+func OptionalPercent(ctx *errctx.Context, m map[string]interface{}, key string, dv float64) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parsePercent)
+	if !ok {
+		return dv
+	}
+	f, err := maputil.OptionalPercent(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// OptionalPercentRange fetches a value from the map, converts it to a fraction
+// and ensures it is within the range, sending any errors to the given context.
+func OptionalPercentRange(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.NumberRange,
+	dv float64,
+) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parsePercent)
+	if !ok {
+		return dv
+	}
+	f, err := maputil.OptionalPercentRange(src, key, r, dv)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// OptionalRate fetches a value from the map and converts it to a rate, sending
+// any errors to the given context.
+func OptionalRate(ctx *errctx.Context, m map[string]interface{}, key string, dv maputil.Rate) maputil.Rate {
+	defer ctx.UseKey(m, key)
+	r, err := maputil.OptionalRate(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return r
+}
+
+// OptionalRegexp fetches a value from the map and converts it to a regular
+// expression, sending any errors to the given context.
+func OptionalRegexp(ctx *errctx.Context, m map[string]interface{}, key string, dv *regexp.Regexp) *regexp.Regexp {
+	defer ctx.UseKey(m, key)
+	re, err := maputil.OptionalRegexp(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return re
+}
+
 // OptionalString fetches a value from the map and converts it to a string,
 // sending any errors to the given context.
 func OptionalString(ctx *errctx.Context, m map[string]interface{}, key, dv string) string {
+	defer ctx.UseKey(m, key)
 	s, err := maputil.OptionalString(m, key, dv)
 	ctx.ErrorWithKey(err, key)
 	return s
@@ -69,11 +417,136 @@ func OptionalString(ctx *errctx.Context, m map[string]interface{}, key, dv strin
 // and ensures it is one of the allowed values, sending any errors to the given
 // context.
 func OptionalStringEnum(ctx *errctx.Context, m map[string]interface{}, key string, allowed []string, dv string) string {
+	defer ctx.UseKey(m, key)
 	s, err := maputil.OptionalStringEnum(m, key, allowed, dv)
 	ctx.ErrorWithKey(err, key)
 	return s
 }
 
+// OptionalStringLength fetches a value from the map and converts it to a
+// string and ensures its length is within the range, sending any errors to the
+// given context.
+func OptionalStringLength(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.LengthRange,
+	dv string,
+) string {
+	defer ctx.UseKey(m, key)
+	s, err := maputil.OptionalStringLength(m, key, r, dv)
+	ctx.ErrorWithKey(err, key)
+	return s
+}
+
+// OptionalStringMap fetches an object from the map and attempts to convert all
+// of its values to strings, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// strings, possibly resulting in a map with fewer keys than the object in the
+// map.
+func OptionalStringMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]string {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.OptionalObject(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return stringMap(ctx, key, o)
+}
+
+// OptionalStringPattern fetches a value from the map and converts it to a
+// string and ensures it matches the regular expression, sending any errors to
+// the given context.
+func OptionalStringPattern(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	re *regexp.Regexp,
+	dv string,
+) string {
+	defer ctx.UseKey(m, key)
+	s, err := maputil.OptionalStringPattern(m, key, re, dv)
+	ctx.ErrorWithKey(err, key)
+	return s
+}
+
+// OptionalTime fetches a value from the map and converts it to a time, sending
+// any errors to the given context.
+func OptionalTime(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	dv time.Time,
+	layouts ...string,
+) time.Time {
+	defer ctx.UseKey(m, key)
+	t, err := maputil.OptionalTime(m, key, dv, layouts...)
+	ctx.ErrorWithKey(err, key)
+	return t
+}
+
+// OptionalURL fetches a value from the map and converts it to an absolute URL,
+// sending any errors to the given context.
+func OptionalURL(ctx *errctx.Context, m map[string]interface{}, key string, dv *url.URL) *url.URL {
+	defer ctx.UseKey(m, key)
+	u, err := maputil.OptionalURL(m, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// OptionalUint8 fetches a value from the map and converts it to an 8-bit
+// unsigned integer, sending any errors to the given context.
+func OptionalUint8(ctx *errctx.Context, m map[string]interface{}, key string, dv uint8) uint8 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return dv
+	}
+	u, err := maputil.OptionalUint8(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// OptionalUint16 fetches a value from the map and converts it to a 16-bit
+// unsigned integer, sending any errors to the given context.
+func OptionalUint16(ctx *errctx.Context, m map[string]interface{}, key string, dv uint16) uint16 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return dv
+	}
+	u, err := maputil.OptionalUint16(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// OptionalUint32 fetches a value from the map and converts it to a 32-bit
+// unsigned integer, sending any errors to the given context.
+func OptionalUint32(ctx *errctx.Context, m map[string]interface{}, key string, dv uint32) uint32 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return dv
+	}
+	u, err := maputil.OptionalUint32(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// OptionalUint64 fetches a value from the map and converts it to a 64-bit
+// unsigned integer, sending any errors to the given context.
+func OptionalUint64(ctx *errctx.Context, m map[string]interface{}, key string, dv uint64) uint64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return dv
+	}
+	u, err := maputil.OptionalUint64(src, key, dv)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
 // OptionalBooleanArray fetches an array from the map and attempts to convert
 // all elements to booleans, sending any errors to the given context.
 //
@@ -81,6 +554,7 @@ func OptionalStringEnum(ctx *errctx.Context, m map[string]interface{}, key strin
 // booleans, possibly resulting in an array with fewer items than the array in
 // the map.
 func OptionalBooleanArray(ctx *errctx.Context, m map[string]interface{}, key string) []bool {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -91,10 +565,13 @@ func OptionalBooleanArray(ctx *errctx.Context, m map[string]interface{}, key str
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	ba := make([]bool, 0, len(a))
 	for i, iv := range a {
-		bv, err := maputil.AsBoolean(iv)
+		if ctx.Canceled() {
+			break
+		}
+		bv, err := AsBoolean(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -102,10 +579,45 @@ func OptionalBooleanArray(ctx *errctx.Context, m map[string]interface{}, key str
 
 		ba = append(ba, bv)
 	}
-	ctx.Path.Pop()
 	return ba
 }
 
+// OptionalBooleanArrayAligned fetches an array from the map and attempts to
+// convert all elements to booleans, sending any errors to the given context.
+//
+// Unlike OptionalBooleanArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func OptionalBooleanArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]bool, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return booleanArrayAligned(ctx, key, a)
+}
+
+// OptionalBooleanArrayDefault fetches an array from the map and attempts to
+// convert all elements to booleans, sending any errors to the given context.
+//
+// The default value is returned if the key is missing or is not an array. As
+// with OptionalBooleanArray, elements which can not be converted to booleans
+// are discarded.
+func OptionalBooleanArrayDefault(ctx *errctx.Context, m map[string]interface{}, key string, dv []bool) []bool {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return dv
+	}
+	if !ok {
+		return dv
+	}
+	return booleanArray(ctx, key, a)
+}
+
 // OptionalIntegerArray fetches an array from the map and attempts to convert
 // all elements to integers, sending any errors to the given context.
 //
@@ -113,6 +625,7 @@ func OptionalBooleanArray(ctx *errctx.Context, m map[string]interface{}, key str
 // integers, possibly resulting in an array with fewer items than the array in
 // the map.
 func OptionalIntegerArray(ctx *errctx.Context, m map[string]interface{}, key string) []int64 {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -122,10 +635,13 @@ func OptionalIntegerArray(ctx *errctx.Context, m map[string]interface{}, key str
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	ia := make([]int64, 0, len(a))
 	for i, iv := range a {
-		v, err := maputil.AsInteger(iv)
+		if ctx.Canceled() {
+			break
+		}
+		v, err := AsInteger(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -133,10 +649,45 @@ func OptionalIntegerArray(ctx *errctx.Context, m map[string]interface{}, key str
 
 		ia = append(ia, v)
 	}
-	ctx.Path.Pop()
 	return ia
 }
 
+// OptionalIntegerArrayAligned fetches an array from the map and attempts to
+// convert all elements to integers, sending any errors to the given context.
+//
+// Unlike OptionalIntegerArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func OptionalIntegerArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]int64, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return integerArrayAligned(ctx, key, a)
+}
+
+// OptionalIntegerArrayDefault fetches an array from the map and attempts to
+// convert all elements to integers, sending any errors to the given context.
+//
+// The default value is returned if the key is missing or is not an array. As
+// with OptionalIntegerArray, elements which can not be converted to integers
+// are discarded.
+func OptionalIntegerArrayDefault(ctx *errctx.Context, m map[string]interface{}, key string, dv []int64) []int64 {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return dv
+	}
+	if !ok {
+		return dv
+	}
+	return integerArray(ctx, key, a)
+}
+
 // OptionalNumberArray fetches an array from the map and attempts to convert
 // all elements to numbers, sending any errors to the given context.
 //
@@ -144,6 +695,7 @@ func OptionalIntegerArray(ctx *errctx.Context, m map[string]interface{}, key str
 // numbers, possibly resulting in an array with fewer items than the array in
 // the map.
 func OptionalNumberArray(ctx *errctx.Context, m map[string]interface{}, key string) []float64 {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -153,10 +705,13 @@ func OptionalNumberArray(ctx *errctx.Context, m map[string]interface{}, key stri
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	na := make([]float64, 0, len(a))
 	for i, iv := range a {
-		v, err := maputil.AsNumber(iv)
+		if ctx.Canceled() {
+			break
+		}
+		v, err := AsNumber(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -164,10 +719,45 @@ func OptionalNumberArray(ctx *errctx.Context, m map[string]interface{}, key stri
 
 		na = append(na, v)
 	}
-	ctx.Path.Pop()
 	return na
 }
 
+// OptionalNumberArrayAligned fetches an array from the map and attempts to
+// convert all elements to numbers, sending any errors to the given context.
+//
+// Unlike OptionalNumberArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func OptionalNumberArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]float64, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return numberArrayAligned(ctx, key, a)
+}
+
+// OptionalNumberArrayDefault fetches an array from the map and attempts to
+// convert all elements to numbers, sending any errors to the given context.
+//
+// The default value is returned if the key is missing or is not an array. As
+// with OptionalNumberArray, elements which can not be converted to numbers are
+// discarded.
+func OptionalNumberArrayDefault(ctx *errctx.Context, m map[string]interface{}, key string, dv []float64) []float64 {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return dv
+	}
+	if !ok {
+		return dv
+	}
+	return numberArray(ctx, key, a)
+}
+
 // OptionalObjectArray fetches an array from the map and attempts to convert
 // all elements to objects, sending any errors to the given context.
 //
@@ -175,6 +765,7 @@ func OptionalNumberArray(ctx *errctx.Context, m map[string]interface{}, key stri
 // objects, possibly resulting in an array with fewer items than the array in
 // the map.
 func OptionalObjectArray(ctx *errctx.Context, m map[string]interface{}, key string) []map[string]interface{} {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -184,9 +775,12 @@ func OptionalObjectArray(ctx *errctx.Context, m map[string]interface{}, key stri
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	oa := make([]map[string]interface{}, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsObject(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -195,10 +789,74 @@ func OptionalObjectArray(ctx *errctx.Context, m map[string]interface{}, key stri
 
 		oa = append(oa, v)
 	}
-	ctx.Path.Pop()
 	return oa
 }
 
+// OptionalObjectArrayAligned fetches an array from the map and attempts to
+// convert all elements to objects, sending any errors to the given context.
+//
+// Unlike OptionalObjectArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func OptionalObjectArrayAligned(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+) ([]map[string]interface{}, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return objectArrayAligned(ctx, key, a)
+}
+
+// OptionalObjectArrayDefault fetches an array from the map and attempts to
+// convert all elements to objects, sending any errors to the given context.
+//
+// The default value is returned if the key is missing or is not an array. As
+// with OptionalObjectArray, elements which can not be converted to objects are
+// discarded.
+func OptionalObjectArrayDefault(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	dv []map[string]interface{},
+) []map[string]interface{} {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return dv
+	}
+	if !ok {
+		return dv
+	}
+	return objectArray(ctx, key, a)
+}
+
+// OptionalObjectArrayFunc fetches an array from the map and calls fn for each
+// element with the context path positioned at the element, such as key[2],
+// sending any errors to the given context.
+//
+// Elements which are not objects are reported and skipped. The return value
+// indicates if the array was present.
+func OptionalObjectArrayFunc(ctx *errctx.Context, m map[string]interface{}, key string, fn ObjectFunc) bool {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	objectArrayFunc(ctx, key, a, fn)
+	return true
+}
+
 // OptionalStringArray fetches an array from the map and attempts to convert
 // all elements to strings, sending any errors to the given context.
 //
@@ -206,6 +864,7 @@ func OptionalObjectArray(ctx *errctx.Context, m map[string]interface{}, key stri
 // strings, possibly resulting in an array with fewer items than the array in
 // the map.
 func OptionalStringArray(ctx *errctx.Context, m map[string]interface{}, key string) []string {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -215,9 +874,12 @@ func OptionalStringArray(ctx *errctx.Context, m map[string]interface{}, key stri
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	sa := make([]string, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsString(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -226,10 +888,45 @@ func OptionalStringArray(ctx *errctx.Context, m map[string]interface{}, key stri
 
 		sa = append(sa, v)
 	}
-	ctx.Path.Pop()
 	return sa
 }
 
+// OptionalStringArrayAligned fetches an array from the map and attempts to
+// convert all elements to strings, sending any errors to the given context.
+//
+// Unlike OptionalStringArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func OptionalStringArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]string, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return stringArrayAligned(ctx, key, a)
+}
+
+// OptionalStringArrayDefault fetches an array from the map and attempts to
+// convert all elements to strings, sending any errors to the given context.
+//
+// The default value is returned if the key is missing or is not an array. As
+// with OptionalStringArray, elements which can not be converted to strings are
+// discarded.
+func OptionalStringArrayDefault(ctx *errctx.Context, m map[string]interface{}, key string, dv []string) []string {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return dv
+	}
+	if !ok {
+		return dv
+	}
+	return stringArray(ctx, key, a)
+}
+
 // OptionalStringEnumArray fetches an array from the map, attempts to
 // convert all elmenents to strings and check that they match the allowed enum
 // values, sending any errors to the given context.
@@ -238,6 +935,7 @@ func OptionalStringArray(ctx *errctx.Context, m map[string]interface{}, key stri
 // strings, as well as any strings which do not match the allowed enum values.
 // This may result in an array with fewer items than the array in the map.
 func OptionalStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key string, allowed []string) []string {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.OptionalArray(m, key, nil)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -247,9 +945,12 @@ func OptionalStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	sa := make([]string, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsString(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -262,6 +963,39 @@ func OptionalStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key
 
 		sa = append(sa, v)
 	}
-	ctx.Path.Pop()
 	return sa
 }
+
+// OptionalIntegerMatrix fetches an array of arrays from the map and attempts
+// to convert all inner elements to integers, sending any errors to the given
+// context.
+//
+// Positions are preserved: inner elements which can not be converted are left
+// as the zero value, and rows which are not arrays are left as nil. Errors are
+// reported with the path of the element, such as key[2][3].
+func OptionalIntegerMatrix(ctx *errctx.Context, m map[string]interface{}, key string) [][]int64 {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return integerMatrix(ctx, key, a)
+}
+
+// OptionalStringMatrix fetches an array of arrays from the map and attempts to
+// convert all inner elements to strings, sending any errors to the given
+// context.
+//
+// Positions are preserved: inner elements which can not be converted are left
+// as the zero value, and rows which are not arrays are left as nil. Errors are
+// reported with the path of the element, such as key[2][3].
+func OptionalStringMatrix(ctx *errctx.Context, m map[string]interface{}, key string) [][]string {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.OptionalArray(m, key, nil)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return stringMatrix(ctx, key, a)
+}
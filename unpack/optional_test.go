@@ -206,6 +206,46 @@ func TestOptionalObject(t *testing.T) {
 	})
 }
 
+func TestOptionalObjectFunc(t *testing.T) {
+	m := map[string]interface{}{
+		testKeyGood: map[string]interface{}{"a": "A"},
+		testKeyBad:  testNumber,
+	}
+	t.Parallel()
+	t.Run("Good", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		called := unpack.OptionalObjectFunc(ctx, d, testKeyGood, func(ctx *errctx.Context, o map[string]interface{}) {
+			require.Equal(t, testKeyGood, ctx.Path.String())
+			require.Equal(t, "A", unpack.RequireString(ctx, o, "a"))
+		})
+		require.True(t, called)
+		require.Zero(t, ctx.ErrorCount())
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		called := unpack.OptionalObjectFunc(ctx, d, testKeyMissing, func(*errctx.Context, map[string]interface{}) {
+			t.Fatal("function called for missing value")
+		})
+		require.False(t, called)
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("BadType", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		called := unpack.OptionalObjectFunc(ctx, d, testKeyBad, func(*errctx.Context, map[string]interface{}) {
+			t.Fatal("function called for invalid value")
+		})
+		require.False(t, called)
+		require.Equal(t, 1, ctx.ErrorCount())
+	})
+}
+
 func TestOptionalString(t *testing.T) {
 	m := map[string]interface{}{
 		testKeyGood: testString,
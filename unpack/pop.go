@@ -0,0 +1,382 @@
+package unpack
+
+import (
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+)
+
+// PopArray removes a value from the map and converts it to an array,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopArray(ctx *errctx.Context, m map[string]interface{}, key string) ([]interface{}, bool) {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.PopArray(m, key)
+	ctx.ErrorWithKey(err, key)
+	return a, ok
+}
+
+// PopBase64Bytes removes a value from the map and converts it to bytes
+// encoded as a base64 string, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopBase64Bytes(ctx *errctx.Context, m map[string]interface{}, key string) ([]byte, bool) {
+	defer ctx.UseKey(m, key)
+	b, ok, err := maputil.PopBase64Bytes(m, key)
+	ctx.ErrorWithKey(err, key)
+	return b, ok
+}
+
+// PopBigFloat removes a value from the map and converts it to an
+// arbitrary-precision floating point number, sending any errors to the
+// given context.
+//
+// The second return value indicates if the key was present.
+func PopBigFloat(ctx *errctx.Context, m map[string]interface{}, key string) (*big.Float, bool) {
+	defer ctx.UseKey(m, key)
+	f, ok, err := maputil.PopBigFloat(m, key)
+	ctx.ErrorWithKey(err, key)
+	return f, ok
+}
+
+// PopBigInt removes a value from the map and converts it to an
+// arbitrary-precision integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopBigInt(ctx *errctx.Context, m map[string]interface{}, key string) (*big.Int, bool) {
+	defer ctx.UseKey(m, key)
+	i, ok, err := maputil.PopBigInt(m, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopBoolean removes a value from the map and converts it to a boolean,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopBoolean(ctx *errctx.Context, m map[string]interface{}, key string) (bool, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseBoolean)
+	if !ok {
+		return false, true
+	}
+	b, ok, err := maputil.PopBoolean(src, key)
+	ctx.ErrorWithKey(err, key)
+	return b, ok
+}
+
+// PopByteSize removes a value from the map and converts it to a number of
+// bytes, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopByteSize(ctx *errctx.Context, m map[string]interface{}, key string) (int64, bool) {
+	defer ctx.UseKey(m, key)
+	i, ok, err := maputil.PopByteSize(m, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopCIDR removes a value from the map and converts it to an IP network,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopCIDR(ctx *errctx.Context, m map[string]interface{}, key string) (*net.IPNet, bool) {
+	defer ctx.UseKey(m, key)
+	n, ok, err := maputil.PopCIDR(m, key)
+	ctx.ErrorWithKey(err, key)
+	return n, ok
+}
+
+// PopDuration removes a value from the map and converts it to a duration,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopDuration(ctx *errctx.Context, m map[string]interface{}, key string, unit time.Duration) (time.Duration, bool) {
+	defer ctx.UseKey(m, key)
+	d, ok, err := maputil.PopDuration(m, key, unit)
+	ctx.ErrorWithKey(err, key)
+	return d, ok
+}
+
+// PopHostPort removes a value from the map and converts it to a host and
+// port, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopHostPort(ctx *errctx.Context, m map[string]interface{}, key string) (maputil.HostPort, bool) {
+	defer ctx.UseKey(m, key)
+	hp, ok, err := maputil.PopHostPort(m, key)
+	ctx.ErrorWithKey(err, key)
+	return hp, ok
+}
+
+// PopIP removes a value from the map and converts it to an IP address,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopIP(ctx *errctx.Context, m map[string]interface{}, key string) (net.IP, bool) {
+	defer ctx.UseKey(m, key)
+	ip, ok, err := maputil.PopIP(m, key)
+	ctx.ErrorWithKey(err, key)
+	return ip, ok
+}
+
+// PopInt8 removes a value from the map and converts it to an 8-bit integer,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopInt8(ctx *errctx.Context, m map[string]interface{}, key string) (int8, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0, true
+	}
+	i, ok, err := maputil.PopInt8(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopInt16 removes a value from the map and converts it to a 16-bit
+// integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopInt16(ctx *errctx.Context, m map[string]interface{}, key string) (int16, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0, true
+	}
+	i, ok, err := maputil.PopInt16(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopInt32 removes a value from the map and converts it to a 32-bit
+// integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopInt32(ctx *errctx.Context, m map[string]interface{}, key string) (int32, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0, true
+	}
+	i, ok, err := maputil.PopInt32(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopInteger removes a value from the map and converts it to an integer,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopInteger(ctx *errctx.Context, m map[string]interface{}, key string) (int64, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0, true
+	}
+	i, ok, err := maputil.PopInteger(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i, ok
+}
+
+// PopNull removes a value from the map and ensures it was null, sending any
+// errors to the given context.
+//
+// The return value indicates if the key was present.
+func PopNull(ctx *errctx.Context, m map[string]interface{}, key string) bool {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNull)
+	if !ok {
+		return true
+	}
+	ok, err := maputil.PopNull(src, key)
+	ctx.ErrorWithKey(err, key)
+	return ok
+}
+
+// PopNumber removes a value from the map and converts it to a number,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopNumber(ctx *errctx.Context, m map[string]interface{}, key string) (float64, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNumber)
+	if !ok {
+		return 0, true
+	}
+	n, ok, err := maputil.PopNumber(src, key)
+	ctx.ErrorWithKey(err, key)
+	return n, ok
+}
+
+// PopObject removes a value from the map and converts it to an object,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopObject(ctx *errctx.Context, m map[string]interface{}, key string) (map[string]interface{}, bool) {
+	defer ctx.UseKey(m, key)
+	o, ok, err := maputil.PopObject(m, key)
+	ctx.ErrorWithKey(err, key)
+	return o, ok
+}
+
+// PopPercent removes a value from the map and converts it to a fraction,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopPercent(ctx *errctx.Context, m map[string]interface{}, key string) (float64, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parsePercent)
+	if !ok {
+		return 0, true
+	}
+	f, ok, err := maputil.PopPercent(src, key)
+	ctx.ErrorWithKey(err, key)
+	return f, ok
+}
+
+// PopRate removes a value from the map and converts it to a rate, sending
+// any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopRate(ctx *errctx.Context, m map[string]interface{}, key string) (maputil.Rate, bool) {
+	defer ctx.UseKey(m, key)
+	r, ok, err := maputil.PopRate(m, key)
+	ctx.ErrorWithKey(err, key)
+	return r, ok
+}
+
+// PopRegexp removes a value from the map and converts it to a regular
+// expression, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopRegexp(ctx *errctx.Context, m map[string]interface{}, key string) (*regexp.Regexp, bool) {
+	defer ctx.UseKey(m, key)
+	re, ok, err := maputil.PopRegexp(m, key)
+	ctx.ErrorWithKey(err, key)
+	return re, ok
+}
+
+// PopString removes a value from the map and converts it to a string,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopString(ctx *errctx.Context, m map[string]interface{}, key string) (string, bool) {
+	defer ctx.UseKey(m, key)
+	s, ok, err := maputil.PopString(m, key)
+	ctx.ErrorWithKey(err, key)
+	return s, ok
+}
+
+// PopStringEnum removes a value from the map, converts it to a string, and
+// ensures it is one of the given values, sending any errors to the given
+// context.
+//
+// The second return value indicates if the key was present.
+func PopStringEnum(ctx *errctx.Context, m map[string]interface{}, key string, values []string) (string, bool) {
+	defer ctx.UseKey(m, key)
+	s, ok, err := maputil.PopStringEnum(m, key, values)
+	ctx.ErrorWithKey(err, key)
+	return s, ok
+}
+
+// PopTime removes a value from the map and converts it to a time, sending
+// any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopTime(ctx *errctx.Context, m map[string]interface{}, key string, layouts ...string) (time.Time, bool) {
+	defer ctx.UseKey(m, key)
+	t, ok, err := maputil.PopTime(m, key, layouts...)
+	ctx.ErrorWithKey(err, key)
+	return t, ok
+}
+
+// PopURL removes a value from the map and converts it to an absolute URL,
+// sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopURL(ctx *errctx.Context, m map[string]interface{}, key string) (*url.URL, bool) {
+	defer ctx.UseKey(m, key)
+	u, ok, err := maputil.PopURL(m, key)
+	ctx.ErrorWithKey(err, key)
+	return u, ok
+}
+
+// PopUint8 removes a value from the map and converts it to an 8-bit
+// unsigned integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopUint8(ctx *errctx.Context, m map[string]interface{}, key string) (uint8, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0, true
+	}
+	u, ok, err := maputil.PopUint8(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u, ok
+}
+
+// PopUint16 removes a value from the map and converts it to a 16-bit
+// unsigned integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopUint16(ctx *errctx.Context, m map[string]interface{}, key string) (uint16, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0, true
+	}
+	u, ok, err := maputil.PopUint16(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u, ok
+}
+
+// PopUint32 removes a value from the map and converts it to a 32-bit
+// unsigned integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopUint32(ctx *errctx.Context, m map[string]interface{}, key string) (uint32, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0, true
+	}
+	u, ok, err := maputil.PopUint32(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u, ok
+}
+
+// PopUint64 removes a value from the map and converts it to a 64-bit
+// unsigned integer, sending any errors to the given context.
+//
+// The second return value indicates if the key was present.
+func PopUint64(ctx *errctx.Context, m map[string]interface{}, key string) (uint64, bool) {
+	defer ctx.UseKey(m, key)
+	defer delete(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0, true
+	}
+	u, ok, err := maputil.PopUint64(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u, ok
+}
@@ -0,0 +1,82 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestPop(t *testing.T) {
+	t.Parallel()
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"name":    "x",
+			"port":    80,
+			"timeout": "1m",
+			"mode":    "fast",
+			"extra":   nil,
+		}
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		s, ok := unpack.PopString(ctx, m, "name")
+		require.True(t, ok)
+		require.Equal(t, "x", s)
+		u, ok := unpack.PopUint16(ctx, m, "port")
+		require.True(t, ok)
+		require.Equal(t, uint16(80), u)
+		d, ok := unpack.PopDuration(ctx, m, "timeout", time.Second)
+		require.True(t, ok)
+		require.Equal(t, time.Minute, d)
+		s, ok = unpack.PopStringEnum(ctx, m, "mode", []string{"fast", "slow"})
+		require.True(t, ok)
+		require.Equal(t, "fast", s)
+		require.True(t, unpack.PopNull(ctx, m, "extra"))
+		require.Empty(t, m)
+		require.Zero(t, ctx.ErrorCount())
+
+		_, ok = unpack.PopString(ctx, m, "name")
+		require.False(t, ok)
+		require.False(t, unpack.PopNull(ctx, m, "extra"))
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"port": "http", "debug": 1}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		i, ok := unpack.PopInteger(ctx, m, "port")
+		require.True(t, ok)
+		require.Zero(t, i)
+		_, ok = unpack.PopBoolean(ctx, m, "debug")
+		require.True(t, ok)
+		require.Empty(t, m)
+		require.Equal(t, []string{
+			`port: invalid type string; expected integer`,
+			`debug: invalid type integer; expected boolean`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("Lenient", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{"port": "8080", "debug": "yes", "proxy": "", "ratio": "half"}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		ctx.Lenient = true
+		i, ok := unpack.PopInteger(ctx, m, "port")
+		require.True(t, ok)
+		require.Equal(t, int64(8080), i)
+		b, ok := unpack.PopBoolean(ctx, m, "debug")
+		require.True(t, ok)
+		require.True(t, b)
+		require.True(t, unpack.PopNull(ctx, m, "proxy"))
+		_, ok = unpack.PopNumber(ctx, m, "ratio")
+		require.True(t, ok)
+		require.Empty(t, m)
+		require.Equal(t, []string{
+			`ratio: invalid value "half"; cannot parse as number`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+}
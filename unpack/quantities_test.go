@@ -0,0 +1,39 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestQuantities(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"memory":    "512Mi",
+		"disk":      "2TB",
+		"threshold": "75%",
+		"ratio":     "120%",
+		"limit":     "100/s",
+		"burst":     "fast",
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, int64(512<<20), unpack.RequireByteSize(ctx, m, "memory"))
+	require.Equal(t, int64(2e12), unpack.RequireByteSizeRange(ctx, m, "disk", maputil.IntegerAtMost(1e12)))
+	require.Equal(t, int64(1<<20), unpack.OptionalByteSizeRange(ctx, m, "cache", maputil.IntegerAtLeast(0), 1<<20))
+	require.Equal(t, 0.75, unpack.RequirePercent(ctx, m, "threshold"))
+	require.Equal(t, 0.5, unpack.OptionalPercentRange(ctx, m, "ratio", maputil.NumberBetween(0, 1), 0.5))
+	require.Equal(t, maputil.Rate{Count: 100, Per: time.Second}, unpack.RequireRate(ctx, m, "limit"))
+	require.Equal(t, maputil.Rate{}, unpack.OptionalRate(ctx, m, "burst", maputil.Rate{}))
+	require.Zero(t, unpack.OptionalPercent(ctx, m, "missing", 0))
+	require.Equal(t, []string{
+		`disk: invalid value 2000000000000; maximum is 1000000000000`,
+		`ratio: invalid value 1.2; maximum is 1`,
+		`burst: invalid value "fast"; expected rate such as "100/s" or "5/10m"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
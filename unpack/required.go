@@ -1,6 +1,12 @@
 package unpack
 
 import (
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"time"
+
 	"github.com/tvarney/maputil"
 	"github.com/tvarney/maputil/errctx"
 	"github.com/tvarney/maputil/mpath"
@@ -9,23 +15,217 @@ import (
 // RequireArray fetches a value from the map and converts it to an array,
 // sending any errors to the given context.
 func RequireArray(ctx *errctx.Context, m map[string]interface{}, key string) []interface{} {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	ctx.ErrorWithKey(err, key)
 	return a
 }
 
+// RequireArrayLength fetches a value from the map and converts it to an array
+// and ensures its length is within the range, sending any errors to the given
+// context.
+func RequireArrayLength(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.LengthRange,
+) []interface{} {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArrayLength(m, key, r)
+	ctx.ErrorWithKey(err, key)
+	return a
+}
+
+// RequireBase64Bytes fetches a value from the map and converts it to bytes
+// encoded as a base64 string, sending any errors to the given context.
+func RequireBase64Bytes(ctx *errctx.Context, m map[string]interface{}, key string) []byte {
+	defer ctx.UseKey(m, key)
+	b, err := maputil.RequireBase64Bytes(m, key)
+	ctx.ErrorWithKey(err, key)
+	return b
+}
+
+// RequireBigFloat fetches a value from the map and converts it to an
+// arbitrary-precision floating point number, sending any errors to the given
+// context.
+func RequireBigFloat(ctx *errctx.Context, m map[string]interface{}, key string) *big.Float {
+	defer ctx.UseKey(m, key)
+	f, err := maputil.RequireBigFloat(m, key)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// RequireBigInt fetches a value from the map and converts it to an
+// arbitrary-precision integer, sending any errors to the given context.
+func RequireBigInt(ctx *errctx.Context, m map[string]interface{}, key string) *big.Int {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.RequireBigInt(m, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
 // RequireBoolean fetches a value from the map and converts it to a boolean,
 // sending any errors to the given context.
 func RequireBoolean(ctx *errctx.Context, m map[string]interface{}, key string) bool {
-	b, err := maputil.RequireBoolean(m, key)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseBoolean)
+	if !ok {
+		return false
+	}
+	b, err := maputil.RequireBoolean(src, key)
 	ctx.ErrorWithKey(err, key)
 	return b
 }
 
+// RequireBooleanMap fetches an object from the map and attempts to convert all
+// of its values to booleans, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// booleans, possibly resulting in a map with fewer keys than the object in the
+// map.
+func RequireBooleanMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]bool {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return booleanMap(ctx, key, o)
+}
+
+// RequireByteSize fetches a value from the map and converts it to a number of
+// bytes, sending any errors to the given context.
+func RequireByteSize(ctx *errctx.Context, m map[string]interface{}, key string) int64 {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.RequireByteSize(m, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// RequireByteSizeRange fetches a value from the map, converts it to a number
+// of bytes and ensures it is within the range, sending any errors to the given
+// context.
+func RequireByteSizeRange(ctx *errctx.Context, m map[string]interface{}, key string, r maputil.IntegerRange) int64 {
+	defer ctx.UseKey(m, key)
+	i, err := maputil.RequireByteSizeRange(m, key, r)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// RequireCIDR fetches a value from the map and converts it to an IP network,
+// sending any errors to the given context.
+func RequireCIDR(ctx *errctx.Context, m map[string]interface{}, key string) *net.IPNet {
+	defer ctx.UseKey(m, key)
+	n, err := maputil.RequireCIDR(m, key)
+	ctx.ErrorWithKey(err, key)
+	return n
+}
+
+// RequireDuration fetches a value from the map and converts it to a duration,
+// sending any errors to the given context.
+func RequireDuration(ctx *errctx.Context, m map[string]interface{}, key string, unit time.Duration) time.Duration {
+	defer ctx.UseKey(m, key)
+	d, err := maputil.RequireDuration(m, key, unit)
+	ctx.ErrorWithKey(err, key)
+	return d
+}
+
+// RequireHostPort fetches a value from the map and converts it to a host and
+// port, sending any errors to the given context.
+func RequireHostPort(ctx *errctx.Context, m map[string]interface{}, key string) maputil.HostPort {
+	defer ctx.UseKey(m, key)
+	hp, err := maputil.RequireHostPort(m, key)
+	ctx.ErrorWithKey(err, key)
+	return hp
+}
+
+// RequireIP fetches a value from the map and converts it to an IP address,
+// sending any errors to the given context.
+func RequireIP(ctx *errctx.Context, m map[string]interface{}, key string) net.IP {
+	defer ctx.UseKey(m, key)
+	ip, err := maputil.RequireIP(m, key)
+	ctx.ErrorWithKey(err, key)
+	return ip
+}
+
+// RequireInt8 fetches a value from the map and converts it to an 8-bit
+// integer, sending any errors to the given context.
+func RequireInt8(ctx *errctx.Context, m map[string]interface{}, key string) int8 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0
+	}
+	i, err := maputil.RequireInt8(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// RequireInt16 fetches a value from the map and converts it to a 16-bit
+// integer, sending any errors to the given context.
+func RequireInt16(ctx *errctx.Context, m map[string]interface{}, key string) int16 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0
+	}
+	i, err := maputil.RequireInt16(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// RequireInt32 fetches a value from the map and converts it to a 32-bit
+// integer, sending any errors to the given context.
+func RequireInt32(ctx *errctx.Context, m map[string]interface{}, key string) int32 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0
+	}
+	i, err := maputil.RequireInt32(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
 // RequireInteger fetches a value from the map and converts it to an integer,
 // sending any errors to the given context.
 func RequireInteger(ctx *errctx.Context, m map[string]interface{}, key string) int64 {
-	i, err := maputil.RequireInteger(m, key)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0
+	}
+	i, err := maputil.RequireInteger(src, key)
+	ctx.ErrorWithKey(err, key)
+	return i
+}
+
+// RequireIntegerMap fetches an object from the map and attempts to convert all
+// of its values to integers, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// integers, possibly resulting in a map with fewer keys than the object in the
+// map.
+func RequireIntegerMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]int64 {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return integerMap(ctx, key, o)
+}
+
+// RequireIntegerRange fetches a value from the map and converts it to an
+// integer and ensures it is within the range, sending any errors to the given
+// context.
+func RequireIntegerRange(ctx *errctx.Context, m map[string]interface{}, key string, r maputil.IntegerRange) int64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseInteger)
+	if !ok {
+		return 0
+	}
+	i, err := maputil.RequireIntegerRange(src, key, r)
 	ctx.ErrorWithKey(err, key)
 	return i
 }
@@ -33,13 +233,52 @@ func RequireInteger(ctx *errctx.Context, m map[string]interface{}, key string) i
 // RequireNull fetches a value from the map and ensures it is nil, sending any
 // errors to the given context.
 func RequireNull(ctx *errctx.Context, m map[string]interface{}, key string) {
-	ctx.ErrorWithKey(maputil.RequireNull(m, key), key)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNull)
+	if !ok {
+		return
+	}
+	ctx.ErrorWithKey(maputil.RequireNull(src, key), key)
 }
 
 // RequireNumber fetches a value from the map and converts it to a number,
 // sending any errors to the given context.
 func RequireNumber(ctx *errctx.Context, m map[string]interface{}, key string) float64 {
-	n, err := maputil.RequireNumber(m, key)
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNumber)
+	if !ok {
+		return 0
+	}
+	n, err := maputil.RequireNumber(src, key)
+	ctx.ErrorWithKey(err, key)
+	return n
+}
+
+// RequireNumberMap fetches an object from the map and attempts to convert all
+// of its values to numbers, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// numbers, possibly resulting in a map with fewer keys than the object in the
+// map.
+func RequireNumberMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]float64 {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return numberMap(ctx, key, o)
+}
+
+// RequireNumberRange fetches a value from the map and converts it to a number
+// and ensures it is within the range, sending any errors to the given context.
+func RequireNumberRange(ctx *errctx.Context, m map[string]interface{}, key string, r maputil.NumberRange) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseNumber)
+	if !ok {
+		return 0
+	}
+	n, err := maputil.RequireNumberRange(src, key, r)
 	ctx.ErrorWithKey(err, key)
 	return n
 }
@@ -47,14 +286,98 @@ func RequireNumber(ctx *errctx.Context, m map[string]interface{}, key string) fl
 // RequireObject fetches a value from the map and converts it to an object,
 // sending any errors to the given context.
 func RequireObject(ctx *errctx.Context, m map[string]interface{}, key string) map[string]interface{} {
+	defer ctx.UseKey(m, key)
 	o, err := maputil.RequireObject(m, key)
 	ctx.ErrorWithKey(err, key)
 	return o
 }
 
+// ObjectFunc is a function which unpacks an object.
+//
+// The context given to an ObjectFunc has its path positioned at the object.
+type ObjectFunc func(ctx *errctx.Context, m map[string]interface{})
+
+// RequireObjectFunc fetches a value from the map, converts it to an object,
+// and calls fn with the context path positioned under the key, sending any
+// errors to the given context.
+//
+// The function is not called if the value is missing or is not an object.
+func RequireObjectFunc(ctx *errctx.Context, m map[string]interface{}, key string, fn ObjectFunc) {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return
+	}
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	fn(ctx, o)
+}
+
+// RequireObjectMap fetches an object from the map and attempts to convert all
+// of its values to objects, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// objects, possibly resulting in a map with fewer keys than the object in the
+// map.
+func RequireObjectMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]map[string]interface{} {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return objectMap(ctx, key, o)
+}
+
+// RequirePercent fetches a value from the map and converts it to a fraction,
+// sending any errors to the given context.
+func RequirePercent(ctx *errctx.Context, m map[string]interface{}, key string) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parsePercent)
+	if !ok {
+		return 0
+	}
+	f, err := maputil.RequirePercent(src, key)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// RequirePercentRange fetches a value from the map, converts it to a fraction
+// and ensures it is within the range, sending any errors to the given context.
+func RequirePercentRange(ctx *errctx.Context, m map[string]interface{}, key string, r maputil.NumberRange) float64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parsePercent)
+	if !ok {
+		return 0
+	}
+	f, err := maputil.RequirePercentRange(src, key, r)
+	ctx.ErrorWithKey(err, key)
+	return f
+}
+
+// RequireRate fetches a value from the map and converts it to a rate, sending
+// any errors to the given context.
+func RequireRate(ctx *errctx.Context, m map[string]interface{}, key string) maputil.Rate {
+	defer ctx.UseKey(m, key)
+	r, err := maputil.RequireRate(m, key)
+	ctx.ErrorWithKey(err, key)
+	return r
+}
+
+// RequireRegexp fetches a value from the map and converts it to a regular
+// expression, sending any errors to the given context.
+func RequireRegexp(ctx *errctx.Context, m map[string]interface{}, key string) *regexp.Regexp {
+	defer ctx.UseKey(m, key)
+	re, err := maputil.RequireRegexp(m, key)
+	ctx.ErrorWithKey(err, key)
+	return re
+}
+
 // RequireString fetches a value from the map and converts it to a string,
 // sending any errors to the given context.
 func RequireString(ctx *errctx.Context, m map[string]interface{}, key string) string {
+	defer ctx.UseKey(m, key)
 	s, err := maputil.RequireString(m, key)
 	ctx.ErrorWithKey(err, key)
 	return s
@@ -64,11 +387,118 @@ func RequireString(ctx *errctx.Context, m map[string]interface{}, key string) st
 // and ensures it is one of the allowed values, sending any errors to the given
 // context.
 func RequireStringEnum(ctx *errctx.Context, m map[string]interface{}, key string, allowed []string) string {
+	defer ctx.UseKey(m, key)
 	s, err := maputil.RequireStringEnum(m, key, allowed)
 	ctx.ErrorWithKey(err, key)
 	return s
 }
 
+// RequireStringLength fetches a value from the map and converts it to a string
+// and ensures its length is within the range, sending any errors to the given
+// context.
+func RequireStringLength(ctx *errctx.Context, m map[string]interface{}, key string, r maputil.LengthRange) string {
+	defer ctx.UseKey(m, key)
+	s, err := maputil.RequireStringLength(m, key, r)
+	ctx.ErrorWithKey(err, key)
+	return s
+}
+
+// RequireStringMap fetches an object from the map and attempts to convert all
+// of its values to strings, sending any errors to the given context.
+//
+// This function will discard any keys whose values can not be converted to
+// strings, possibly resulting in a map with fewer keys than the object in the
+// map.
+func RequireStringMap(ctx *errctx.Context, m map[string]interface{}, key string) map[string]string {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return stringMap(ctx, key, o)
+}
+
+// RequireStringPattern fetches a value from the map and converts it to a
+// string and ensures it matches the regular expression, sending any errors to
+// the given context.
+func RequireStringPattern(ctx *errctx.Context, m map[string]interface{}, key string, re *regexp.Regexp) string {
+	defer ctx.UseKey(m, key)
+	s, err := maputil.RequireStringPattern(m, key, re)
+	ctx.ErrorWithKey(err, key)
+	return s
+}
+
+// RequireTime fetches a value from the map and converts it to a time, sending
+// any errors to the given context.
+func RequireTime(ctx *errctx.Context, m map[string]interface{}, key string, layouts ...string) time.Time {
+	defer ctx.UseKey(m, key)
+	t, err := maputil.RequireTime(m, key, layouts...)
+	ctx.ErrorWithKey(err, key)
+	return t
+}
+
+// RequireURL fetches a value from the map and converts it to an absolute URL,
+// sending any errors to the given context.
+func RequireURL(ctx *errctx.Context, m map[string]interface{}, key string) *url.URL {
+	defer ctx.UseKey(m, key)
+	u, err := maputil.RequireURL(m, key)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// RequireUint8 fetches a value from the map and converts it to an 8-bit
+// unsigned integer, sending any errors to the given context.
+func RequireUint8(ctx *errctx.Context, m map[string]interface{}, key string) uint8 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0
+	}
+	u, err := maputil.RequireUint8(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// RequireUint16 fetches a value from the map and converts it to a 16-bit
+// unsigned integer, sending any errors to the given context.
+func RequireUint16(ctx *errctx.Context, m map[string]interface{}, key string) uint16 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0
+	}
+	u, err := maputil.RequireUint16(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// RequireUint32 fetches a value from the map and converts it to a 32-bit
+// unsigned integer, sending any errors to the given context.
+func RequireUint32(ctx *errctx.Context, m map[string]interface{}, key string) uint32 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0
+	}
+	u, err := maputil.RequireUint32(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
+// RequireUint64 fetches a value from the map and converts it to a 64-bit
+// unsigned integer, sending any errors to the given context.
+func RequireUint64(ctx *errctx.Context, m map[string]interface{}, key string) uint64 {
+	defer ctx.UseKey(m, key)
+	src, ok := lenientMap(ctx, m, key, parseUnsigned)
+	if !ok {
+		return 0
+	}
+	u, err := maputil.RequireUint64(src, key)
+	ctx.ErrorWithKey(err, key)
+	return u
+}
+
 // RequireBooleanArray fetches an array from the map and attempts to convert
 // all elements to booleans, sending any errors to the given context.
 //
@@ -76,6 +506,7 @@ func RequireStringEnum(ctx *errctx.Context, m map[string]interface{}, key string
 // booleans, possibly resulting in an array with fewer items than the array in
 // the map.
 func RequireBooleanArray(ctx *errctx.Context, m map[string]interface{}, key string) []bool {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -86,10 +517,13 @@ func RequireBooleanArray(ctx *errctx.Context, m map[string]interface{}, key stri
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	ba := make([]bool, 0, len(a))
 	for i, iv := range a {
-		bv, err := maputil.AsBoolean(iv)
+		if ctx.Canceled() {
+			break
+		}
+		bv, err := AsBoolean(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -97,10 +531,26 @@ func RequireBooleanArray(ctx *errctx.Context, m map[string]interface{}, key stri
 
 		ba = append(ba, bv)
 	}
-	ctx.Path.Pop()
 	return ba
 }
 
+// RequireBooleanArrayAligned fetches an array from the map and attempts to
+// convert all elements to booleans, sending any errors to the given context.
+//
+// Unlike RequireBooleanArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func RequireBooleanArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]bool, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return booleanArrayAligned(ctx, key, a)
+}
+
 // RequireIntegerArray fetches an array from the map and attempts to convert
 // all elements to integers, sending any errors to the given context.
 //
@@ -108,6 +558,7 @@ func RequireBooleanArray(ctx *errctx.Context, m map[string]interface{}, key stri
 // integers, possibly resulting in an array with fewer items than the array in
 // the map.
 func RequireIntegerArray(ctx *errctx.Context, m map[string]interface{}, key string) []int64 {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -117,10 +568,13 @@ func RequireIntegerArray(ctx *errctx.Context, m map[string]interface{}, key stri
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	ia := make([]int64, 0, len(a))
 	for i, iv := range a {
-		v, err := maputil.AsInteger(iv)
+		if ctx.Canceled() {
+			break
+		}
+		v, err := AsInteger(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -128,10 +582,26 @@ func RequireIntegerArray(ctx *errctx.Context, m map[string]interface{}, key stri
 
 		ia = append(ia, v)
 	}
-	ctx.Path.Pop()
 	return ia
 }
 
+// RequireIntegerArrayAligned fetches an array from the map and attempts to
+// convert all elements to integers, sending any errors to the given context.
+//
+// Unlike RequireIntegerArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func RequireIntegerArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]int64, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return integerArrayAligned(ctx, key, a)
+}
+
 // RequireNumberArray fetches an array from the map and attempts to convert
 // all elements to numbers, sending any errors to the given context.
 //
@@ -139,6 +609,7 @@ func RequireIntegerArray(ctx *errctx.Context, m map[string]interface{}, key stri
 // numbers, possibly resulting in an array with fewer items than the array in
 // the map.
 func RequireNumberArray(ctx *errctx.Context, m map[string]interface{}, key string) []float64 {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -148,10 +619,13 @@ func RequireNumberArray(ctx *errctx.Context, m map[string]interface{}, key strin
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	na := make([]float64, 0, len(a))
 	for i, iv := range a {
-		v, err := maputil.AsNumber(iv)
+		if ctx.Canceled() {
+			break
+		}
+		v, err := AsNumber(ctx, iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
 			continue
@@ -159,10 +633,26 @@ func RequireNumberArray(ctx *errctx.Context, m map[string]interface{}, key strin
 
 		na = append(na, v)
 	}
-	ctx.Path.Pop()
 	return na
 }
 
+// RequireNumberArrayAligned fetches an array from the map and attempts to
+// convert all elements to numbers, sending any errors to the given context.
+//
+// Unlike RequireNumberArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func RequireNumberArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]float64, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return numberArrayAligned(ctx, key, a)
+}
+
 // RequireObjectArray fetches an array from the map and attempts to convert
 // all elements to objects, sending any errors to the given context.
 //
@@ -170,6 +660,7 @@ func RequireNumberArray(ctx *errctx.Context, m map[string]interface{}, key strin
 // objects, possibly resulting in an array with fewer items than the array in
 // the map.
 func RequireObjectArray(ctx *errctx.Context, m map[string]interface{}, key string) []map[string]interface{} {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -179,9 +670,12 @@ func RequireObjectArray(ctx *errctx.Context, m map[string]interface{}, key strin
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	oa := make([]map[string]interface{}, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsObject(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -190,10 +684,45 @@ func RequireObjectArray(ctx *errctx.Context, m map[string]interface{}, key strin
 
 		oa = append(oa, v)
 	}
-	ctx.Path.Pop()
 	return oa
 }
 
+// RequireObjectArrayAligned fetches an array from the map and attempts to
+// convert all elements to objects, sending any errors to the given context.
+//
+// Unlike RequireObjectArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func RequireObjectArrayAligned(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+) ([]map[string]interface{}, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return objectArrayAligned(ctx, key, a)
+}
+
+// RequireObjectArrayFunc fetches an array from the map and calls fn for each
+// element with the context path positioned at the element, such as key[2],
+// sending any errors to the given context.
+//
+// Elements which are not objects are reported and skipped.
+func RequireObjectArrayFunc(ctx *errctx.Context, m map[string]interface{}, key string, fn ObjectFunc) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return
+	}
+	objectArrayFunc(ctx, key, a, fn)
+}
+
 // RequireStringArray fetches an array from the map and attempts to convert
 // all elements to strings, sending any errors to the given context.
 //
@@ -201,6 +730,7 @@ func RequireObjectArray(ctx *errctx.Context, m map[string]interface{}, key strin
 // strings, possibly resulting in an array with fewer items than the array in
 // the map.
 func RequireStringArray(ctx *errctx.Context, m map[string]interface{}, key string) []string {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -210,9 +740,12 @@ func RequireStringArray(ctx *errctx.Context, m map[string]interface{}, key strin
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	sa := make([]string, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsString(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -221,10 +754,26 @@ func RequireStringArray(ctx *errctx.Context, m map[string]interface{}, key strin
 
 		sa = append(sa, v)
 	}
-	ctx.Path.Pop()
 	return sa
 }
 
+// RequireStringArrayAligned fetches an array from the map and attempts to
+// convert all elements to strings, sending any errors to the given context.
+//
+// Unlike RequireStringArray, elements which can not be converted are left as
+// the zero value so that the result has the same length as the array in the
+// map. The second return value is a mask recording which elements were
+// converted.
+func RequireStringArrayAligned(ctx *errctx.Context, m map[string]interface{}, key string) ([]string, []bool) {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil, nil
+	}
+	return stringArrayAligned(ctx, key, a)
+}
+
 // RequireStringEnumArray fetches an array from the map, attempts to
 // convert all elmenents to strings and check that they match the allowed enum
 // values, sending any errors to the given context.
@@ -233,6 +782,7 @@ func RequireStringArray(ctx *errctx.Context, m map[string]interface{}, key strin
 // strings, as well as any strings which do not match the allowed enum values.
 // This may result in an array with fewer items than the array in the map.
 func RequireStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key string, allowed []string) []string {
+	defer ctx.UseKey(m, key)
 	a, err := maputil.RequireArray(m, key)
 	if err != nil {
 		ctx.ErrorWithKey(err, key)
@@ -242,9 +792,12 @@ func RequireStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key s
 		return nil
 	}
 
-	ctx.Path.Add(mpath.Key(key))
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
 	sa := make([]string, 0, len(a))
 	for i, iv := range a {
+		if ctx.Canceled() {
+			break
+		}
 		v, err := maputil.AsString(iv)
 		if err != nil {
 			ctx.ErrorWithIndex(err, i)
@@ -257,6 +810,39 @@ func RequireStringEnumArray(ctx *errctx.Context, m map[string]interface{}, key s
 
 		sa = append(sa, v)
 	}
-	ctx.Path.Pop()
 	return sa
 }
+
+// RequireIntegerMatrix fetches an array of arrays from the map and attempts to
+// convert all inner elements to integers, sending any errors to the given
+// context.
+//
+// Positions are preserved: inner elements which can not be converted are left
+// as the zero value, and rows which are not arrays are left as nil. Errors are
+// reported with the path of the element, such as key[2][3].
+func RequireIntegerMatrix(ctx *errctx.Context, m map[string]interface{}, key string) [][]int64 {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return integerMatrix(ctx, key, a)
+}
+
+// RequireStringMatrix fetches an array of arrays from the map and attempts to
+// convert all inner elements to strings, sending any errors to the given
+// context.
+//
+// Positions are preserved: inner elements which can not be converted are left
+// as the zero value, and rows which are not arrays are left as nil. Errors are
+// reported with the path of the element, such as key[2][3].
+func RequireStringMatrix(ctx *errctx.Context, m map[string]interface{}, key string) [][]string {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return nil
+	}
+	return stringMatrix(ctx, key, a)
+}
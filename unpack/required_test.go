@@ -1,6 +1,9 @@
 package unpack_test
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -183,6 +186,48 @@ func TestRequireObject(t *testing.T) {
 	})
 }
 
+func TestRequireObjectFunc(t *testing.T) {
+	m := map[string]interface{}{
+		testKeyGood: map[string]interface{}{"a": "A", "b": 2},
+		testKeyBad:  testNumber,
+	}
+	t.Parallel()
+	t.Run("Good", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		called := false
+		unpack.RequireObjectFunc(ctx, d, testKeyGood, func(ctx *errctx.Context, o map[string]interface{}) {
+			called = true
+			require.Equal(t, "A", unpack.RequireString(ctx, o, "a"))
+			unpack.RequireString(ctx, o, "b")
+		})
+		require.True(t, called)
+		require.Equal(t, 1, ctx.ErrorCount())
+		require.True(t, strings.HasPrefix(sb.String(), testKeyGood+".b: "))
+		require.Len(t, ctx.Path.Elements, 0)
+	})
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		unpack.RequireObjectFunc(ctx, d, testKeyMissing, func(*errctx.Context, map[string]interface{}) {
+			t.Fatal("function called for missing value")
+		})
+		require.Equal(t, 1, ctx.ErrorCount())
+	})
+	t.Run("BadType", func(t *testing.T) {
+		t.Parallel()
+		d := maputil.Copy(m)
+		ctx := errctx.New(errctx.ErrorDiscarder{})
+		unpack.RequireObjectFunc(ctx, d, testKeyBad, func(*errctx.Context, map[string]interface{}) {
+			t.Fatal("function called for invalid value")
+		})
+		require.Equal(t, 1, ctx.ErrorCount())
+	})
+}
+
 func TestRequireString(t *testing.T) {
 	m := map[string]interface{}{
 		testKeyGood: testString,
@@ -343,6 +388,18 @@ func TestRequireIntegerArray(t *testing.T) {
 	})
 }
 
+func TestRequireIntegerArrayCanceled(t *testing.T) {
+	t.Parallel()
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	m := map[string]interface{}{testKeyGood: []interface{}{1, 2, 3}}
+	ctx := errctx.New(errctx.ErrorDiscarder{})
+	ctx.SetContext(c)
+	require.Len(t, unpack.RequireIntegerArray(ctx, m, testKeyGood), 0)
+	require.Equal(t, 1, ctx.ErrorCount())
+	require.True(t, errors.Is(ctx.LastError(), errctx.ErrCanceled))
+}
+
 func TestRequireNumberArray(t *testing.T) {
 	barray := []float64{1.4, 2.8, -100.0}
 	m := map[string]interface{}{
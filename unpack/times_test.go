@@ -0,0 +1,35 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestTimes(t *testing.T) {
+	t.Parallel()
+	m := map[string]interface{}{
+		"timeout":  "30s",
+		"interval": 5,
+		"delay":    "soon",
+		"created":  "2026-01-02T15:04:05Z",
+		"updated":  "01/02/2026",
+	}
+	sb := &strings.Builder{}
+	ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+	require.Equal(t, 30*time.Second, unpack.RequireDuration(ctx, m, "timeout", 0))
+	require.Equal(t, 5*time.Second, unpack.OptionalDuration(ctx, m, "interval", time.Second, time.Minute))
+	require.Equal(t, time.Minute, unpack.OptionalDuration(ctx, m, "delay", time.Second, time.Minute))
+	require.Equal(t, 2026, unpack.RequireTime(ctx, m, "created").Year())
+	require.True(t, unpack.OptionalTime(ctx, m, "updated", time.Time{}, "2006-01-02").IsZero())
+	require.True(t, unpack.RequireTime(ctx, m, "missing").IsZero())
+	require.Equal(t, []string{
+		`delay: invalid value "soon"; expected duration such as "1m30s"`,
+		`updated: invalid value "01/02/2026"; expected time in format "2006-01-02"`,
+		`missing: missing required value "missing"`,
+	}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+}
@@ -0,0 +1,145 @@
+package unpack
+
+import (
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// TupleElement converts the value at one position of a tuple, returning any
+// error encountered. The context is that of the tuple, and should only be used
+// to check if it is lenient; errors are reported by the caller.
+type TupleElement func(ctx *errctx.Context, v interface{}) error
+
+// BooleanElement returns a tuple element which converts the value to a
+// boolean and stores it in p.
+func BooleanElement(p *bool) TupleElement {
+	return func(ctx *errctx.Context, v interface{}) error {
+		b, err := AsBoolean(ctx, v)
+		if err == nil {
+			*p = b
+		}
+		return err
+	}
+}
+
+// IntegerElement returns a tuple element which converts the value to an
+// integer and stores it in p.
+func IntegerElement(p *int64) TupleElement {
+	return func(ctx *errctx.Context, v interface{}) error {
+		i, err := AsInteger(ctx, v)
+		if err == nil {
+			*p = i
+		}
+		return err
+	}
+}
+
+// NumberElement returns a tuple element which converts the value to a number
+// and stores it in p.
+func NumberElement(p *float64) TupleElement {
+	return func(ctx *errctx.Context, v interface{}) error {
+		n, err := AsNumber(ctx, v)
+		if err == nil {
+			*p = n
+		}
+		return err
+	}
+}
+
+// ObjectElement returns a tuple element which converts the value to an
+// object and stores it in p.
+func ObjectElement(p *map[string]interface{}) TupleElement {
+	return func(ctx *errctx.Context, v interface{}) error {
+		o, err := maputil.AsObject(v)
+		if err == nil {
+			*p = o
+		}
+		return err
+	}
+}
+
+// StringElement returns a tuple element which converts the value to a string
+// and stores it in p.
+func StringElement(p *string) TupleElement {
+	return func(ctx *errctx.Context, v interface{}) error {
+		s, err := maputil.AsString(v)
+		if err == nil {
+			*p = s
+		}
+		return err
+	}
+}
+
+// RequireTuple fetches an array from the map, ensures its length is within
+// the range, and converts the element at each position with the tuple element
+// at the same position, sending any errors to the given context.
+//
+// Errors converting an element are reported at the index of the element,
+// such as key[1]. Elements are converted even if the length is out of range.
+// Positions without an element, either because the array is too short or
+// because the tuple element is nil, are skipped. The return value is true if
+// the array was present and no errors were found.
+func RequireTuple(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.LengthRange,
+	elems ...TupleElement,
+) bool {
+	defer ctx.UseKey(m, key)
+	a, err := maputil.RequireArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return false
+	}
+	return unpackTuple(ctx, key, a, r, elems)
+}
+
+// OptionalTuple fetches an array from the map, ensures its length is within
+// the range, and converts the element at each position with the tuple element
+// at the same position, sending any errors to the given context.
+//
+// See RequireTuple for details. The return value is false if the array was
+// missing.
+func OptionalTuple(
+	ctx *errctx.Context,
+	m map[string]interface{},
+	key string,
+	r maputil.LengthRange,
+	elems ...TupleElement,
+) bool {
+	defer ctx.UseKey(m, key)
+	a, ok, err := maputil.GetArray(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	return unpackTuple(ctx, key, a, r, elems)
+}
+
+func unpackTuple(ctx *errctx.Context, key string, a []interface{}, r maputil.LengthRange, elems []TupleElement) bool {
+	valid := true
+	if err := maputil.CheckArrayLength(a, r); err != nil {
+		ctx.ErrorWithKey(err, key)
+		valid = false
+	}
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	for i, elem := range elems {
+		if i >= len(a) || ctx.Canceled() {
+			break
+		}
+		if elem == nil {
+			continue
+		}
+		if err := elem(ctx, a[i]); err != nil {
+			ctx.ErrorWithIndex(err, i)
+			valid = false
+		}
+	}
+	return valid
+}
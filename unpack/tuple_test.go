@@ -0,0 +1,81 @@
+package unpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+func TestTuple(t *testing.T) {
+	t.Parallel()
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"range": []interface{}{10, 20},
+			"route": []interface{}{"GET", "/path", 200},
+			"point": []interface{}{0.5, 1.5, true, map[string]interface{}{}},
+		}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: &strings.Builder{}})
+
+		var lo, hi int64
+		require.True(t, unpack.RequireTuple(ctx, m, "range", maputil.LengthBetween(2, 2),
+			unpack.IntegerElement(&lo), unpack.IntegerElement(&hi)))
+		require.Equal(t, int64(10), lo)
+		require.Equal(t, int64(20), hi)
+
+		var method, path string
+		var status int64
+		require.True(t, unpack.OptionalTuple(ctx, m, "route", maputil.LengthBetween(2, 3),
+			unpack.StringElement(&method), unpack.StringElement(&path), unpack.IntegerElement(&status)))
+		require.Equal(t, "GET", method)
+		require.Equal(t, "/path", path)
+		require.Equal(t, int64(200), status)
+
+		var y float64
+		var flag bool
+		var extra map[string]interface{}
+		require.True(t, unpack.RequireTuple(ctx, m, "point", maputil.LengthAtLeast(2),
+			nil, unpack.NumberElement(&y), unpack.BooleanElement(&flag), unpack.ObjectElement(&extra)))
+		require.Equal(t, 1.5, y)
+		require.True(t, flag)
+		require.NotNil(t, extra)
+
+		require.False(t, unpack.OptionalTuple(ctx, m, "missing", maputil.LengthAtLeast(0)))
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		m := map[string]interface{}{
+			"range": []interface{}{10, "20", 30},
+			"route": []interface{}{"GET"},
+			"pair":  "10,20",
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+
+		var lo, hi int64
+		require.False(t, unpack.RequireTuple(ctx, m, "range", maputil.LengthBetween(2, 2),
+			unpack.IntegerElement(&lo), unpack.IntegerElement(&hi)))
+		require.Equal(t, int64(10), lo)
+		require.Zero(t, hi)
+
+		var method, path string
+		require.False(t, unpack.OptionalTuple(ctx, m, "route", maputil.LengthBetween(2, 3),
+			unpack.StringElement(&method), unpack.StringElement(&path)))
+		require.Equal(t, "GET", method)
+
+		require.False(t, unpack.OptionalTuple(ctx, m, "pair", maputil.LengthAtLeast(0)))
+		require.False(t, unpack.RequireTuple(ctx, m, "missing", maputil.LengthAtLeast(0)))
+		require.Equal(t, []string{
+			`range: invalid value of length 3; maximum length is 2`,
+			`range[1]: invalid type string; expected integer`,
+			`route: invalid value of length 1; minimum length is 2`,
+			`pair: invalid type string; expected array`,
+			`missing: missing required value "missing"`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+}
@@ -0,0 +1,112 @@
+package unpack
+
+import (
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/mpath"
+)
+
+// Union unpacks objects whose format depends on the value of a discriminator
+// key, such as `{"type": "s3", "bucket": "logs"}`.
+//
+// Each variant is registered with the ObjectFunc which unpacks it. The
+// discriminator is read as with RequireStringEnum, so a missing key is
+// reported as a missing required value and an unknown variant as an
+// EnumStringError listing the registered variants.
+type Union struct {
+	Key string
+
+	variants map[string]ObjectFunc
+	names    []string
+}
+
+// NewUnion returns a new union discriminated by the given key.
+func NewUnion(key string) *Union {
+	return &Union{
+		Key:      key,
+		variants: map[string]ObjectFunc{},
+	}
+}
+
+// Register adds a variant to the union, returning the union so that calls may
+// be chained.
+//
+// Register panics if the function is nil or the variant was already
+// registered.
+func (u *Union) Register(variant string, fn ObjectFunc) *Union {
+	if fn == nil {
+		panic("unpack: variant " + variant + " registered with a nil function")
+	}
+	if _, ok := u.variants[variant]; ok {
+		panic("unpack: variant " + variant + " registered twice")
+	}
+	u.variants[variant] = fn
+	u.names = append(u.names, variant)
+	return u
+}
+
+// Variants returns the registered variants in the order they were
+// registered.
+func (u *Union) Variants() []string {
+	names := make([]string, len(u.names))
+	copy(names, u.names)
+	return names
+}
+
+// Unpack reads the discriminator of the object and calls the function of the
+// matching variant with the same context and object, sending any errors to
+// the given context.
+//
+// The variant is returned, or the empty string if the discriminator was
+// missing or invalid, in which case no function is called.
+func (u *Union) Unpack(ctx *errctx.Context, m map[string]interface{}) string {
+	variant, err := maputil.RequireStringEnum(m, u.Key, u.names)
+	// The discriminator is used before the variant is unpacked, so that a
+	// check for unknown keys within the variant function does not report it.
+	ctx.UseKey(m, u.Key)
+	if err != nil {
+		ctx.ErrorWithKey(err, u.Key)
+		return ""
+	}
+	u.variants[variant](ctx, m)
+	return variant
+}
+
+// RequireUnion fetches a value from the map, converts it to an object, and
+// unpacks it with the union with the context path positioned under the key,
+// sending any errors to the given context.
+//
+// The variant of the object is returned, or the empty string if the value was
+// missing or could not be unpacked.
+func RequireUnion(ctx *errctx.Context, m map[string]interface{}, key string, u *Union) string {
+	defer ctx.UseKey(m, key)
+	o, err := maputil.RequireObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return ""
+	}
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	return u.Unpack(ctx, o)
+}
+
+// OptionalUnion fetches a value from the map, converts it to an object, and
+// unpacks it with the union with the context path positioned under the key,
+// sending any errors to the given context.
+//
+// The variant of the object is returned, or the empty string if the value was
+// missing or could not be unpacked.
+func OptionalUnion(ctx *errctx.Context, m map[string]interface{}, key string, u *Union) string {
+	defer ctx.UseKey(m, key)
+	o, ok, err := maputil.GetObject(m, key)
+	if err != nil {
+		ctx.ErrorWithKey(err, key)
+		return ""
+	}
+	if !ok {
+		return ""
+	}
+
+	defer ctx.Leave(ctx.Enter(mpath.Key(key)))
+	return u.Unpack(ctx, o)
+}
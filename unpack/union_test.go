@@ -0,0 +1,117 @@
+package unpack_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tvarney/maputil"
+	"github.com/tvarney/maputil/errctx"
+	"github.com/tvarney/maputil/unpack"
+)
+
+type storage struct {
+	kind   string
+	bucket string
+	path   string
+}
+
+func storageUnion(s *storage) *unpack.Union {
+	return unpack.NewUnion("type").
+		Register("s3", func(ctx *errctx.Context, m map[string]interface{}) {
+			s.kind = "s3"
+			s.bucket = unpack.RequireString(ctx, m, "bucket")
+		}).
+		Register("file", func(ctx *errctx.Context, m map[string]interface{}) {
+			s.kind = "file"
+			s.path = unpack.RequireString(ctx, m, "path")
+		})
+}
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+	t.Run("Dispatch", func(t *testing.T) {
+		t.Parallel()
+		s := &storage{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: &strings.Builder{}})
+		m := map[string]interface{}{
+			"storage": map[string]interface{}{"type": "s3", "bucket": "logs"},
+		}
+		require.Equal(t, "s3", unpack.RequireUnion(ctx, m, "storage", storageUnion(s)))
+		require.Equal(t, storage{kind: "s3", bucket: "logs"}, *s)
+		require.Zero(t, ctx.ErrorCount())
+	})
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		s := &storage{}
+		u := storageUnion(s)
+		require.Equal(t, []string{"s3", "file"}, u.Variants())
+		u.Variants()[0] = "gcs"
+		require.Equal(t, []string{"s3", "file"}, u.Variants())
+
+		m := map[string]interface{}{
+			"primary":   map[string]interface{}{"type": "gcs"},
+			"secondary": map[string]interface{}{"bucket": "logs"},
+			"backup":    map[string]interface{}{"type": "file"},
+			"archive":   "s3://logs",
+		}
+		sb := &strings.Builder{}
+		ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+		require.Empty(t, unpack.RequireUnion(ctx, m, "primary", u))
+		require.Empty(t, unpack.OptionalUnion(ctx, m, "secondary", u))
+		require.Equal(t, "file", unpack.OptionalUnion(ctx, m, "backup", u))
+		require.Empty(t, unpack.OptionalUnion(ctx, m, "archive", u))
+		require.Empty(t, unpack.OptionalUnion(ctx, m, "missing", u))
+		require.Empty(t, unpack.RequireUnion(ctx, m, "missing", u))
+		require.Equal(t, []string{
+			`primary.type: invalid value "gcs"; expected "s3" or "file"`,
+			`secondary.type: missing required value "type"`,
+			`backup.path: missing required value "path"`,
+			`archive: invalid type string; expected object`,
+			`missing: missing required value "missing"`,
+		}, strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"))
+	})
+	t.Run("ErrorType", func(t *testing.T) {
+		t.Parallel()
+		ctx := errctx.New()
+		collector := &errctx.ErrorCollector{}
+		ctx.Handler = collector
+		storageUnion(&storage{}).Unpack(ctx, map[string]interface{}{"type": "gcs"})
+		require.Len(t, collector.Entries, 1)
+		var enumErr maputil.EnumStringError
+		require.True(t, errors.As(collector.Entries[0].Err, &enumErr))
+		require.Equal(t, []string{"s3", "file"}, enumErr.Enum)
+	})
+	t.Run("UnknownKeys", func(t *testing.T) {
+		t.Parallel()
+		u := unpack.NewUnion("type").
+			Register("s3", func(ctx *errctx.Context, m map[string]interface{}) {
+				unpack.RequireString(ctx, m, "bucket")
+				if ctx.Consume {
+					unpack.Leftovers(ctx, m)
+				} else {
+					unpack.CheckUnknownKeys(ctx, m)
+				}
+			})
+
+		for _, consume := range []bool{false, true} {
+			m := map[string]interface{}{"type": "s3", "bucket": "logs", "region": "x"}
+			sb := &strings.Builder{}
+			ctx := errctx.New(&errctx.ErrorPrinter{Stream: sb})
+			ctx.Keys = errctx.NewKeyTracker()
+			ctx.Consume = consume
+			require.Equal(t, "s3", u.Unpack(ctx, m))
+			require.Equal(t, "region: unknown key \"region\"\n", sb.String())
+		}
+	})
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+		fn := func(ctx *errctx.Context, m map[string]interface{}) {}
+		require.Panics(t, func() { storageUnion(&storage{}).Register("s3", fn) })
+	})
+	t.Run("NilFunc", func(t *testing.T) {
+		t.Parallel()
+		require.Panics(t, func() { unpack.NewUnion("type").Register("s3", nil) })
+	})
+}